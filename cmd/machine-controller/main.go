@@ -9,6 +9,10 @@
 package main
 
 import (
+	"context"
+	"fmt"
+	"os"
+
 	_ "github.com/gardener/machine-controller-manager/pkg/util/client/metrics/prometheus" // for client metric registration
 	"github.com/gardener/machine-controller-manager/pkg/util/provider/app"
 	"github.com/gardener/machine-controller-manager/pkg/util/provider/app/options"
@@ -22,25 +26,61 @@ import (
 	"k8s.io/klog/v2"
 
 	"github.com/gardener/machine-controller-manager-provider-openstack/pkg/apis/openstack/install"
+	"github.com/gardener/machine-controller-manager-provider-openstack/pkg/client"
 	"github.com/gardener/machine-controller-manager-provider-openstack/pkg/driver"
+	"github.com/gardener/machine-controller-manager-provider-openstack/pkg/driver/executor"
+	"github.com/gardener/machine-controller-manager-provider-openstack/pkg/tracing"
 )
 
 func main() {
 	s := options.NewMCServer()
 	s.AddFlags(pflag.CommandLine)
 
+	timeouts := executor.Timeouts{}
+	timeouts.AddFlags(pflag.CommandLine)
+
+	rateLimits := client.RateLimits{}
+	rateLimits.AddFlags(pflag.CommandLine)
+
+	httpOpts := client.HTTPOptions{}
+	httpOpts.AddFlags(pflag.CommandLine)
+
+	tracingOpts := tracing.Options{}
+	tracingOpts.AddFlags(pflag.CommandLine)
+
 	flag.InitFlags()
 	logs.InitLogs()
-	defer logs.FlushLogs()
+
+	if err := run(s, tracingOpts, timeouts, rateLimits, httpOpts); err != nil {
+		klog.Error(err)
+		logs.FlushLogs()
+		os.Exit(1)
+	}
+	logs.FlushLogs()
+}
+
+// run runs the machine controller until it stops. Tracing is shut down before it returns, also if the machine
+// controller fails.
+func run(s *options.MCServer, tracingOpts tracing.Options, timeouts executor.Timeouts, rateLimits client.RateLimits, httpOpts client.HTTPOptions) error {
+	shutdownTracing, err := tracing.Setup(context.Background(), tracingOpts)
+	if err != nil {
+		return fmt.Errorf("failed to set up tracing: %w", err)
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			klog.Errorf("failed to shut down tracing: %v", err)
+		}
+	}()
 
 	scheme := runtime.NewScheme()
 	if err := install.AddToScheme(scheme); err != nil {
-		klog.Fatalf("failed to install scheme: %v", err)
+		return fmt.Errorf("failed to install scheme: %w", err)
 	}
 
-	provider := driver.NewOpenstackDriver(serializer.NewCodecFactory(scheme, serializer.EnableStrict).UniversalDecoder())
+	provider := driver.NewOpenstackDriver(serializer.NewCodecFactory(scheme, serializer.EnableStrict).UniversalDecoder(), timeouts, rateLimits, httpOpts)
 
 	if err := app.Run(s, provider); err != nil {
-		klog.Fatalf("failed to run application: %v", err)
+		return fmt.Errorf("failed to run application: %w", err)
 	}
+	return nil
 }
@@ -11,6 +11,12 @@ const (
 	OpenStackCACert string = "caCert"
 	// OpenStackInsecure is a constant for a key name that is part of the OpenStack cloud Credentials.
 	OpenStackInsecure string = "insecure"
+	// OpenStackCACertAppend is a constant for a key name that selects whether the CA certificate is trusted in addition
+	// to the system certificate authorities instead of replacing them.
+	OpenStackCACertAppend string = "caCertAppend"
+	// OpenStackProxyURL is a constant for a key name that holds the URL of the proxy the requests to the OpenStack API
+	// are sent through. It overrides the proxy configured in the environment.
+	OpenStackProxyURL string = "proxyURL"
 	// OpenStackDomainName is a constant for a key name that is part of the OpenStack cloud Credentials.
 	OpenStackDomainName string = "domainName"
 	// OpenStackDomainID is a constant for a key name that is part of the OpenStack cloud Credentials.
@@ -34,15 +40,61 @@ const (
 	// OpenStackApplicationCredentialSecret is a constant for a key name that is part of the OpenStack cloud Credentials.
 	OpenStackApplicationCredentialSecret = "applicationCredentialSecret"
 
+	// OpenStackNextApplicationCredentialID is a constant for a key name that holds the ID of the application credential,
+	// which replaces the current one during a rotation. It is used once the current credentials are rejected.
+	OpenStackNextApplicationCredentialID = OpenStackApplicationCredentialID + ".next"
+	// OpenStackNextApplicationCredentialName is a constant for a key name that holds the name of the application
+	// credential, which replaces the current one during a rotation.
+	OpenStackNextApplicationCredentialName = OpenStackApplicationCredentialName + ".next"
+	// OpenStackNextApplicationCredentialSecret is a constant for a key name that holds the secret of the application
+	// credential, which replaces the current one during a rotation.
+	OpenStackNextApplicationCredentialSecret = OpenStackApplicationCredentialSecret + ".next"
+
 	// OpenStackClientCert is a constant for a key name that is part of the OpenStack cloud Credentials.
 	OpenStackClientCert string = "clientCert"
 	// OpenStackClientKey is a constant for a key name that is part of the OpenStack cloud Credentials.
 	OpenStackClientKey string = "clientKey"
 
+	// OpenStackCloudsYAML is a constant for a key name that holds a clouds.yaml file with the OpenStack cloud Credentials.
+	// It is mutually exclusive with the individual credential keys.
+	OpenStackCloudsYAML string = "clouds.yaml"
+	// OpenStackSecureYAML is a constant for a key name that holds a secure.yaml file, which is merged into the clouds.yaml
+	// file.
+	OpenStackSecureYAML string = "secure.yaml"
+	// OpenStackCloud is a constant for a key name that selects the cloud of the clouds.yaml file.
+	OpenStackCloud string = "cloud"
+
+	// OpenStackIdentityProvider is a constant for a key name that selects the Keystone identity provider an OIDC access
+	// token is exchanged at. It enables the authentication with an access token instead of static credentials.
+	OpenStackIdentityProvider string = "identityProvider"
+	// OpenStackProtocol is a constant for a key name that selects the federation protocol of the identity provider.
+	OpenStackProtocol string = "protocol"
+	// OpenStackAccessToken is a constant for a key name that holds the OIDC access token.
+	OpenStackAccessToken string = "accessToken"
+	// OpenStackAccessTokenFile is a constant for a key name that holds the path of a file containing the OIDC access
+	// token, e.g. a projected service account token.
+	OpenStackAccessTokenFile string = "accessTokenFile"
+
+	// OpenStackEndpointInterface is a constant for a key name that selects the interface of the endpoints in the service
+	// catalog, i.e. "public", "internal" or "admin". The public interface is used by default.
+	OpenStackEndpointInterface string = "endpointInterface"
+	// OpenStackComputeEndpoint is a constant for a key name that overrides the endpoint of the compute service.
+	OpenStackComputeEndpoint string = "computeEndpoint"
+	// OpenStackNetworkEndpoint is a constant for a key name that overrides the endpoint of the network service.
+	OpenStackNetworkEndpoint string = "networkEndpoint"
+	// OpenStackVolumeEndpoint is a constant for a key name that overrides the endpoint of the block storage service.
+	OpenStackVolumeEndpoint string = "volumeEndpoint"
+	// OpenStackImageEndpoint is a constant for a key name that overrides the endpoint of the image service.
+	OpenStackImageEndpoint string = "imageEndpoint"
+
 	// ServerTagClusterPrefix is the prefix used for tags denoting the cluster this server belongs to.
 	ServerTagClusterPrefix = "kubernetes.io-cluster-"
 	// ServerTagRolePrefix is the prefix used for tags denoting the role of the server.
 	ServerTagRolePrefix = "kubernetes.io-role-"
+	// ServerMetadataAvailabilityZone is the metadata key denoting the availability zone the server was created in.
+	ServerMetadataAvailabilityZone = "machine.gardener.cloud-availability-zone"
+	// ServerMetadataFlavor is the metadata key denoting the flavor the server was created with.
+	ServerMetadataFlavor = "machine.gardener.cloud-flavor"
 
 	// UserData is a constant for a key name whose value contains data passed to the server e.g. CloudInit scripts.
 	UserData string = "userData"
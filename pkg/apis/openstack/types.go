@@ -23,12 +23,21 @@ type MachineProviderConfigSpec struct {
 	ImageID string
 	// ImageName is the name of the image used the machine. If ImageID is specified, it takes priority over ImageName.
 	ImageName string
+	// ImageSelector selects the image used by the machine based on its properties. ImageSelector is mutually exclusive
+	// with ImageID and ImageName.
+	ImageSelector *ImageSelector
 	// Region is the region the machine should belong to.
 	Region string
 	// AvailabilityZone is the availability zone the machine belongs.
 	AvailabilityZone string
+	// FallbackAvailabilityZones is an ordered list of availability zones that are tried if the server cannot be scheduled
+	// in the AvailabilityZone because of insufficient capacity.
+	FallbackAvailabilityZones []string
 	// FlavorName is the flavor of the machine.
 	FlavorName string
+	// FallbackFlavorNames is an ordered list of flavors that are tried if the server cannot be created with the FlavorName
+	// because of insufficient capacity or exhausted quota.
+	FallbackFlavorNames []string
 	// KeyName is the name of the key pair used for SSH access.
 	KeyName string
 	// SecurityGroups is a list of security groups the instance should belong to.
@@ -52,9 +61,20 @@ type MachineProviderConfigSpec struct {
 	UseConfigDrive *bool
 	// ServerGroupID is the ID of the server group this instance should belong to.
 	ServerGroupID *string
+	// ManagedServerGroup requests a server group, which is created by the provider if it does not exist and deleted once
+	// its last member is deleted. ManagedServerGroup is mutually exclusive with ServerGroupID.
+	ManagedServerGroup *ManagedServerGroup
+	// SchedulerHints are additional hints passed to the Nova scheduler when the instance is created.
+	SchedulerHints *SchedulerHints
 	// Networks is a list of networks the instance should belong to. Networks is mutually exclusive with the NetworkID option
 	// and only one should be specified.
 	Networks []OpenStackNetwork
+	// FloatingIP configures a floating IP, which is allocated and associated with the instance.
+	FloatingIP *FloatingIP
+	// DataVolumes is a list of additional volumes that are created and attached to the instance.
+	DataVolumes []DataVolume
+	// Timeouts configures the timeouts and the poll interval used while waiting for OpenStack resources.
+	Timeouts *Timeouts
 }
 
 // OpenStackNetwork describes a network this instance should belong to.
@@ -66,3 +86,99 @@ type OpenStackNetwork struct {
 	// PodNetwork specifies whether this network is part of the pod network.
 	PodNetwork bool
 }
+
+// FloatingIP describes the floating IP that is associated with the instance.
+type FloatingIP struct {
+	// NetworkID is the ID of the external network the floating IP is allocated from. It is mutually exclusive with
+	// PoolName.
+	NetworkID string
+	// PoolName is the name of the external network the floating IP is allocated from. It is mutually exclusive with
+	// NetworkID.
+	PoolName string
+	// SubnetID is the ID of the subnet of the external network the floating IP is allocated from.
+	SubnetID *string
+	// ReuseUnassociated specifies whether tagged floating IPs that are not associated with any port are reused instead of
+	// allocating a new floating IP. Reused floating IPs are only disassociated but not released when the instance is deleted.
+	ReuseUnassociated bool
+}
+
+// DataVolume describes an additional volume that is attached to the instance.
+type DataVolume struct {
+	// Name is the suffix that is appended to the machine name to form the name of the volume.
+	Name string
+	// Size is the size of the volume in GB.
+	Size int
+	// Type is the type of the volume.
+	Type *string
+	// DeleteOnTermination specifies whether the volume is deleted together with the instance. Defaults to true.
+	DeleteOnTermination *bool
+}
+
+// ManagedServerGroup describes a server group, which is managed by the provider.
+type ManagedServerGroup struct {
+	// Name is the name of the server group. Nova server groups do not support tags, so the actual name of the group is
+	// prefixed with the cluster and role of the instance.
+	Name string
+	// Policy is the scheduling policy of the server group, one of "affinity", "anti-affinity", "soft-affinity" or
+	// "soft-anti-affinity".
+	Policy string
+}
+
+// SchedulerHints are hints passed to the Nova scheduler. The server group hint is derived from ServerGroupID or
+// ManagedServerGroup.
+type SchedulerHints struct {
+	// DifferentHost places the instance on a compute node that does not host any of the referenced servers.
+	DifferentHost []ServerReference
+	// SameHost places the instance on a compute node that hosts all of the referenced servers.
+	SameHost []ServerReference
+	// BuildNearHostIP is a CIDR, e.g. "192.168.1.1/24", the IP address of the compute node hosting the instance must be
+	// part of.
+	BuildNearHostIP *string
+	// Query is a JSON encoded conditional statement the compute node hosting the instance must satisfy, e.g.
+	// [">=", "$free_ram_mb", 1024].
+	Query *string
+	// TargetCell is the name of the cell the instance is placed in.
+	TargetCell *string
+	// AdditionalProperties are arbitrary scheduler hints, which are passed to the Nova scheduler without validation.
+	AdditionalProperties map[string]string
+}
+
+// ServerReference references a server by ID or by name.
+type ServerReference struct {
+	// ID is the ID of the server.
+	ID string
+	// Name is the name of the server. All servers with the given name are referenced. Name is mutually exclusive with
+	// ID.
+	Name string
+}
+
+// Timeouts configures the timeouts and the poll interval used while waiting for OpenStack resources. Unset fields fall
+// back to the defaults of the driver.
+type Timeouts struct {
+	// ServerCreate is the maximum duration to wait for a server to become active.
+	ServerCreate *metav1.Duration
+	// VolumeCreate is the maximum duration to wait for a volume to become available.
+	VolumeCreate *metav1.Duration
+	// Delete is the maximum duration to wait for a server or a volume to be deleted.
+	Delete *metav1.Duration
+	// PollInterval is the initial interval between two status polls. It grows with exponential backoff.
+	PollInterval *metav1.Duration
+}
+
+// ImageSelector selects an image based on its name, tags, properties and visibility. If multiple images match, the image
+// with the highest version is chosen if a VersionProperty is specified, otherwise the newest image is chosen.
+type ImageSelector struct {
+	// Name is the name of the image.
+	Name *string
+	// Tags is a list of tags the image must have.
+	Tags []string
+	// Properties is a map of properties the image must have with the given values.
+	Properties map[string]string
+	// Visibility is the visibility of the image, e.g. "public", "private", "shared" or "community".
+	Visibility *string
+	// VersionProperty is the name of the image property that contains the semantic version of the image.
+	VersionProperty *string
+	// VersionConstraint is a semantic version constraint, e.g. ">= 1.2, < 2", the version of the image must satisfy.
+	// VersionConstraint requires VersionProperty to be set.
+	VersionConstraint *string
+}
@@ -25,12 +25,24 @@ type MachineProviderConfigSpec struct {
 	ImageID string `json:"imageID"`
 	// ImageName is the name of the image used the machine. If ImageID is specified, it takes priority over ImageName.
 	ImageName string `json:"imageName"`
+	// ImageSelector selects the image used by the machine based on its properties. ImageSelector is mutually exclusive
+	// with ImageID and ImageName.
+	// +optional
+	ImageSelector *ImageSelector `json:"imageSelector,omitempty"`
 	// Region is the region the machine should belong to.
 	Region string `json:"region"`
 	// AvailabilityZone is the availability zone the machine belongs.
 	AvailabilityZone string `json:"availabilityZone"`
+	// FallbackAvailabilityZones is an ordered list of availability zones that are tried if the server cannot be scheduled
+	// in the AvailabilityZone because of insufficient capacity.
+	// +optional
+	FallbackAvailabilityZones []string `json:"fallbackAvailabilityZones,omitempty"`
 	// FlavorName is the flavor of the machine.
 	FlavorName string `json:"flavorName"`
+	// FallbackFlavorNames is an ordered list of flavors that are tried if the server cannot be created with the FlavorName
+	// because of insufficient capacity or exhausted quota.
+	// +optional
+	FallbackFlavorNames []string `json:"fallbackFlavorNames,omitempty"`
 	// KeyName is the name of the key pair used for SSH access.
 	KeyName string `json:"keyName"`
 	// SecurityGroups is a list of security groups the instance should belong to.
@@ -59,9 +71,25 @@ type MachineProviderConfigSpec struct {
 	// ServerGroupID is the ID of the server group this instance should belong to.
 	// +optional
 	ServerGroupID *string `json:"serverGroupID,omitempty"`
+	// ManagedServerGroup requests a server group, which is created by the provider if it does not exist and deleted once
+	// its last member is deleted. ManagedServerGroup is mutually exclusive with ServerGroupID.
+	// +optional
+	ManagedServerGroup *ManagedServerGroup `json:"managedServerGroup,omitempty"`
+	// SchedulerHints are additional hints passed to the Nova scheduler when the instance is created.
+	// +optional
+	SchedulerHints *SchedulerHints `json:"schedulerHints,omitempty"`
 	// Networks is a list of networks the instance should belong to. Networks is mutually exclusive with the NetworkID option
 	// and only one should be specified.
 	Networks []OpenStackNetwork `json:"networks,omitempty"`
+	// FloatingIP configures a floating IP, which is allocated and associated with the instance.
+	// +optional
+	FloatingIP *FloatingIP `json:"floatingIP,omitempty"`
+	// DataVolumes is a list of additional volumes that are created and attached to the instance.
+	// +optional
+	DataVolumes []DataVolume `json:"dataVolumes,omitempty"`
+	// Timeouts configures the timeouts and the poll interval used while waiting for OpenStack resources.
+	// +optional
+	Timeouts *Timeouts `json:"timeouts,omitempty"`
 }
 
 // OpenStackNetwork describes a network this instance should belong to.
@@ -73,3 +101,121 @@ type OpenStackNetwork struct {
 	// PodNetwork specifies whether this network is part of the pod network.
 	PodNetwork bool `json:"podNetwork,omitempty"`
 }
+
+// FloatingIP describes the floating IP that is associated with the instance.
+type FloatingIP struct {
+	// NetworkID is the ID of the external network the floating IP is allocated from. It is mutually exclusive with
+	// PoolName.
+	// +optional
+	NetworkID string `json:"networkID,omitempty"`
+	// PoolName is the name of the external network the floating IP is allocated from. It is mutually exclusive with
+	// NetworkID.
+	// +optional
+	PoolName string `json:"poolName,omitempty"`
+	// SubnetID is the ID of the subnet of the external network the floating IP is allocated from.
+	// +optional
+	SubnetID *string `json:"subnetID,omitempty"`
+	// ReuseUnassociated specifies whether tagged floating IPs that are not associated with any port are reused instead of
+	// allocating a new floating IP. Reused floating IPs are only disassociated but not released when the instance is deleted.
+	// +optional
+	ReuseUnassociated bool `json:"reuseUnassociated,omitempty"`
+}
+
+// DataVolume describes an additional volume that is attached to the instance.
+type DataVolume struct {
+	// Name is the suffix that is appended to the machine name to form the name of the volume.
+	Name string `json:"name"`
+	// Size is the size of the volume in GB.
+	Size int `json:"size"`
+	// Type is the type of the volume.
+	// +optional
+	Type *string `json:"type,omitempty"`
+	// DeleteOnTermination specifies whether the volume is deleted together with the instance. Defaults to true.
+	// +optional
+	DeleteOnTermination *bool `json:"deleteOnTermination,omitempty"`
+}
+
+// ManagedServerGroup describes a server group, which is managed by the provider.
+type ManagedServerGroup struct {
+	// Name is the name of the server group. Nova server groups do not support tags, so the actual name of the group is
+	// prefixed with the cluster and role of the instance.
+	Name string `json:"name"`
+	// Policy is the scheduling policy of the server group, one of "affinity", "anti-affinity", "soft-affinity" or
+	// "soft-anti-affinity".
+	Policy string `json:"policy"`
+}
+
+// SchedulerHints are hints passed to the Nova scheduler. The server group hint is derived from ServerGroupID or
+// ManagedServerGroup.
+type SchedulerHints struct {
+	// DifferentHost places the instance on a compute node that does not host any of the referenced servers.
+	// +optional
+	DifferentHost []ServerReference `json:"differentHost,omitempty"`
+	// SameHost places the instance on a compute node that hosts all of the referenced servers.
+	// +optional
+	SameHost []ServerReference `json:"sameHost,omitempty"`
+	// BuildNearHostIP is a CIDR, e.g. "192.168.1.1/24", the IP address of the compute node hosting the instance must be
+	// part of.
+	// +optional
+	BuildNearHostIP *string `json:"buildNearHostIP,omitempty"`
+	// Query is a JSON encoded conditional statement the compute node hosting the instance must satisfy, e.g.
+	// [">=", "$free_ram_mb", 1024].
+	// +optional
+	Query *string `json:"query,omitempty"`
+	// TargetCell is the name of the cell the instance is placed in.
+	// +optional
+	TargetCell *string `json:"targetCell,omitempty"`
+	// AdditionalProperties are arbitrary scheduler hints, which are passed to the Nova scheduler without validation.
+	// +optional
+	AdditionalProperties map[string]string `json:"additionalProperties,omitempty"`
+}
+
+// ServerReference references a server by ID or by name.
+type ServerReference struct {
+	// ID is the ID of the server.
+	ID string `json:"id,omitempty"`
+	// Name is the name of the server. All servers with the given name are referenced. Name is mutually exclusive with
+	// ID.
+	Name string `json:"name,omitempty"`
+}
+
+// Timeouts configures the timeouts and the poll interval used while waiting for OpenStack resources. Unset fields fall
+// back to the defaults of the driver.
+type Timeouts struct {
+	// ServerCreate is the maximum duration to wait for a server to become active.
+	// +optional
+	ServerCreate *metav1.Duration `json:"serverCreate,omitempty"`
+	// VolumeCreate is the maximum duration to wait for a volume to become available.
+	// +optional
+	VolumeCreate *metav1.Duration `json:"volumeCreate,omitempty"`
+	// Delete is the maximum duration to wait for a server or a volume to be deleted.
+	// +optional
+	Delete *metav1.Duration `json:"delete,omitempty"`
+	// PollInterval is the initial interval between two status polls. It grows with exponential backoff.
+	// +optional
+	PollInterval *metav1.Duration `json:"pollInterval,omitempty"`
+}
+
+// ImageSelector selects an image based on its name, tags, properties and visibility. If multiple images match, the image
+// with the highest version is chosen if a VersionProperty is specified, otherwise the newest image is chosen.
+type ImageSelector struct {
+	// Name is the name of the image.
+	// +optional
+	Name *string `json:"name,omitempty"`
+	// Tags is a list of tags the image must have.
+	// +optional
+	Tags []string `json:"tags,omitempty"`
+	// Properties is a map of properties the image must have with the given values.
+	// +optional
+	Properties map[string]string `json:"properties,omitempty"`
+	// Visibility is the visibility of the image, e.g. "public", "private", "shared" or "community".
+	// +optional
+	Visibility *string `json:"visibility,omitempty"`
+	// VersionProperty is the name of the image property that contains the semantic version of the image.
+	// +optional
+	VersionProperty *string `json:"versionProperty,omitempty"`
+	// VersionConstraint is a semantic version constraint, e.g. ">= 1.2, < 2", the version of the image must satisfy.
+	// VersionConstraint requires VersionProperty to be set.
+	// +optional
+	VersionConstraint *string `json:"versionConstraint,omitempty"`
+}
@@ -13,6 +13,7 @@ import (
 	unsafe "unsafe"
 
 	openstack "github.com/gardener/machine-controller-manager-provider-openstack/pkg/apis/openstack"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	conversion "k8s.io/apimachinery/pkg/conversion"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
@@ -24,6 +25,46 @@ func init() {
 // RegisterConversions adds conversion functions to the given scheme.
 // Public to allow building arbitrary schemes.
 func RegisterConversions(s *runtime.Scheme) error {
+	if err := s.AddGeneratedConversionFunc((*DataVolume)(nil), (*openstack.DataVolume)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_DataVolume_To_openstack_DataVolume(a.(*DataVolume), b.(*openstack.DataVolume), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*openstack.DataVolume)(nil), (*DataVolume)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_openstack_DataVolume_To_v1alpha1_DataVolume(a.(*openstack.DataVolume), b.(*DataVolume), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*FloatingIP)(nil), (*openstack.FloatingIP)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_FloatingIP_To_openstack_FloatingIP(a.(*FloatingIP), b.(*openstack.FloatingIP), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*openstack.FloatingIP)(nil), (*FloatingIP)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_openstack_FloatingIP_To_v1alpha1_FloatingIP(a.(*openstack.FloatingIP), b.(*FloatingIP), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ImageSelector)(nil), (*openstack.ImageSelector)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_ImageSelector_To_openstack_ImageSelector(a.(*ImageSelector), b.(*openstack.ImageSelector), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*openstack.ImageSelector)(nil), (*ImageSelector)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_openstack_ImageSelector_To_v1alpha1_ImageSelector(a.(*openstack.ImageSelector), b.(*ImageSelector), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ManagedServerGroup)(nil), (*openstack.ManagedServerGroup)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_ManagedServerGroup_To_openstack_ManagedServerGroup(a.(*ManagedServerGroup), b.(*openstack.ManagedServerGroup), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*openstack.ManagedServerGroup)(nil), (*ManagedServerGroup)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_openstack_ManagedServerGroup_To_v1alpha1_ManagedServerGroup(a.(*openstack.ManagedServerGroup), b.(*ManagedServerGroup), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*MachineProviderConfig)(nil), (*openstack.MachineProviderConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_MachineProviderConfig_To_openstack_MachineProviderConfig(a.(*MachineProviderConfig), b.(*openstack.MachineProviderConfig), scope)
 	}); err != nil {
@@ -54,9 +95,143 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*SchedulerHints)(nil), (*openstack.SchedulerHints)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_SchedulerHints_To_openstack_SchedulerHints(a.(*SchedulerHints), b.(*openstack.SchedulerHints), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*openstack.SchedulerHints)(nil), (*SchedulerHints)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_openstack_SchedulerHints_To_v1alpha1_SchedulerHints(a.(*openstack.SchedulerHints), b.(*SchedulerHints), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ServerReference)(nil), (*openstack.ServerReference)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_ServerReference_To_openstack_ServerReference(a.(*ServerReference), b.(*openstack.ServerReference), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*openstack.ServerReference)(nil), (*ServerReference)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_openstack_ServerReference_To_v1alpha1_ServerReference(a.(*openstack.ServerReference), b.(*ServerReference), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*Timeouts)(nil), (*openstack.Timeouts)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_Timeouts_To_openstack_Timeouts(a.(*Timeouts), b.(*openstack.Timeouts), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*openstack.Timeouts)(nil), (*Timeouts)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_openstack_Timeouts_To_v1alpha1_Timeouts(a.(*openstack.Timeouts), b.(*Timeouts), scope)
+	}); err != nil {
+		return err
+	}
+	return nil
+}
+
+func autoConvert_v1alpha1_DataVolume_To_openstack_DataVolume(in *DataVolume, out *openstack.DataVolume, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Size = in.Size
+	out.Type = (*string)(unsafe.Pointer(in.Type))
+	out.DeleteOnTermination = (*bool)(unsafe.Pointer(in.DeleteOnTermination))
+	return nil
+}
+
+// Convert_v1alpha1_DataVolume_To_openstack_DataVolume is an autogenerated conversion function.
+func Convert_v1alpha1_DataVolume_To_openstack_DataVolume(in *DataVolume, out *openstack.DataVolume, s conversion.Scope) error {
+	return autoConvert_v1alpha1_DataVolume_To_openstack_DataVolume(in, out, s)
+}
+
+func autoConvert_openstack_DataVolume_To_v1alpha1_DataVolume(in *openstack.DataVolume, out *DataVolume, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Size = in.Size
+	out.Type = (*string)(unsafe.Pointer(in.Type))
+	out.DeleteOnTermination = (*bool)(unsafe.Pointer(in.DeleteOnTermination))
+	return nil
+}
+
+// Convert_openstack_DataVolume_To_v1alpha1_DataVolume is an autogenerated conversion function.
+func Convert_openstack_DataVolume_To_v1alpha1_DataVolume(in *openstack.DataVolume, out *DataVolume, s conversion.Scope) error {
+	return autoConvert_openstack_DataVolume_To_v1alpha1_DataVolume(in, out, s)
+}
+
+func autoConvert_v1alpha1_FloatingIP_To_openstack_FloatingIP(in *FloatingIP, out *openstack.FloatingIP, s conversion.Scope) error {
+	out.NetworkID = in.NetworkID
+	out.PoolName = in.PoolName
+	out.SubnetID = (*string)(unsafe.Pointer(in.SubnetID))
+	out.ReuseUnassociated = in.ReuseUnassociated
+	return nil
+}
+
+// Convert_v1alpha1_FloatingIP_To_openstack_FloatingIP is an autogenerated conversion function.
+func Convert_v1alpha1_FloatingIP_To_openstack_FloatingIP(in *FloatingIP, out *openstack.FloatingIP, s conversion.Scope) error {
+	return autoConvert_v1alpha1_FloatingIP_To_openstack_FloatingIP(in, out, s)
+}
+
+func autoConvert_openstack_FloatingIP_To_v1alpha1_FloatingIP(in *openstack.FloatingIP, out *FloatingIP, s conversion.Scope) error {
+	out.NetworkID = in.NetworkID
+	out.PoolName = in.PoolName
+	out.SubnetID = (*string)(unsafe.Pointer(in.SubnetID))
+	out.ReuseUnassociated = in.ReuseUnassociated
+	return nil
+}
+
+// Convert_openstack_FloatingIP_To_v1alpha1_FloatingIP is an autogenerated conversion function.
+func Convert_openstack_FloatingIP_To_v1alpha1_FloatingIP(in *openstack.FloatingIP, out *FloatingIP, s conversion.Scope) error {
+	return autoConvert_openstack_FloatingIP_To_v1alpha1_FloatingIP(in, out, s)
+}
+
+func autoConvert_v1alpha1_ImageSelector_To_openstack_ImageSelector(in *ImageSelector, out *openstack.ImageSelector, s conversion.Scope) error {
+	out.Name = (*string)(unsafe.Pointer(in.Name))
+	out.Tags = *(*[]string)(unsafe.Pointer(&in.Tags))
+	out.Properties = *(*map[string]string)(unsafe.Pointer(&in.Properties))
+	out.Visibility = (*string)(unsafe.Pointer(in.Visibility))
+	out.VersionProperty = (*string)(unsafe.Pointer(in.VersionProperty))
+	out.VersionConstraint = (*string)(unsafe.Pointer(in.VersionConstraint))
+	return nil
+}
+
+// Convert_v1alpha1_ImageSelector_To_openstack_ImageSelector is an autogenerated conversion function.
+func Convert_v1alpha1_ImageSelector_To_openstack_ImageSelector(in *ImageSelector, out *openstack.ImageSelector, s conversion.Scope) error {
+	return autoConvert_v1alpha1_ImageSelector_To_openstack_ImageSelector(in, out, s)
+}
+
+func autoConvert_openstack_ImageSelector_To_v1alpha1_ImageSelector(in *openstack.ImageSelector, out *ImageSelector, s conversion.Scope) error {
+	out.Name = (*string)(unsafe.Pointer(in.Name))
+	out.Tags = *(*[]string)(unsafe.Pointer(&in.Tags))
+	out.Properties = *(*map[string]string)(unsafe.Pointer(&in.Properties))
+	out.Visibility = (*string)(unsafe.Pointer(in.Visibility))
+	out.VersionProperty = (*string)(unsafe.Pointer(in.VersionProperty))
+	out.VersionConstraint = (*string)(unsafe.Pointer(in.VersionConstraint))
 	return nil
 }
 
+// Convert_openstack_ImageSelector_To_v1alpha1_ImageSelector is an autogenerated conversion function.
+func Convert_openstack_ImageSelector_To_v1alpha1_ImageSelector(in *openstack.ImageSelector, out *ImageSelector, s conversion.Scope) error {
+	return autoConvert_openstack_ImageSelector_To_v1alpha1_ImageSelector(in, out, s)
+}
+
+func autoConvert_v1alpha1_ManagedServerGroup_To_openstack_ManagedServerGroup(in *ManagedServerGroup, out *openstack.ManagedServerGroup, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Policy = in.Policy
+	return nil
+}
+
+// Convert_v1alpha1_ManagedServerGroup_To_openstack_ManagedServerGroup is an autogenerated conversion function.
+func Convert_v1alpha1_ManagedServerGroup_To_openstack_ManagedServerGroup(in *ManagedServerGroup, out *openstack.ManagedServerGroup, s conversion.Scope) error {
+	return autoConvert_v1alpha1_ManagedServerGroup_To_openstack_ManagedServerGroup(in, out, s)
+}
+
+func autoConvert_openstack_ManagedServerGroup_To_v1alpha1_ManagedServerGroup(in *openstack.ManagedServerGroup, out *ManagedServerGroup, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Policy = in.Policy
+	return nil
+}
+
+// Convert_openstack_ManagedServerGroup_To_v1alpha1_ManagedServerGroup is an autogenerated conversion function.
+func Convert_openstack_ManagedServerGroup_To_v1alpha1_ManagedServerGroup(in *openstack.ManagedServerGroup, out *ManagedServerGroup, s conversion.Scope) error {
+	return autoConvert_openstack_ManagedServerGroup_To_v1alpha1_ManagedServerGroup(in, out, s)
+}
+
 func autoConvert_v1alpha1_MachineProviderConfig_To_openstack_MachineProviderConfig(in *MachineProviderConfig, out *openstack.MachineProviderConfig, s conversion.Scope) error {
 	if err := Convert_v1alpha1_MachineProviderConfigSpec_To_openstack_MachineProviderConfigSpec(&in.Spec, &out.Spec, s); err != nil {
 		return err
@@ -84,9 +259,12 @@ func Convert_openstack_MachineProviderConfig_To_v1alpha1_MachineProviderConfig(i
 func autoConvert_v1alpha1_MachineProviderConfigSpec_To_openstack_MachineProviderConfigSpec(in *MachineProviderConfigSpec, out *openstack.MachineProviderConfigSpec, s conversion.Scope) error {
 	out.ImageID = in.ImageID
 	out.ImageName = in.ImageName
+	out.ImageSelector = (*openstack.ImageSelector)(unsafe.Pointer(in.ImageSelector))
 	out.Region = in.Region
 	out.AvailabilityZone = in.AvailabilityZone
+	out.FallbackAvailabilityZones = *(*[]string)(unsafe.Pointer(&in.FallbackAvailabilityZones))
 	out.FlavorName = in.FlavorName
+	out.FallbackFlavorNames = *(*[]string)(unsafe.Pointer(&in.FallbackFlavorNames))
 	out.KeyName = in.KeyName
 	out.SecurityGroups = *(*[]string)(unsafe.Pointer(&in.SecurityGroups))
 	out.Tags = *(*map[string]string)(unsafe.Pointer(&in.Tags))
@@ -98,7 +276,12 @@ func autoConvert_v1alpha1_MachineProviderConfigSpec_To_openstack_MachineProvider
 	out.RootDiskType = (*string)(unsafe.Pointer(in.RootDiskType))
 	out.UseConfigDrive = (*bool)(unsafe.Pointer(in.UseConfigDrive))
 	out.ServerGroupID = (*string)(unsafe.Pointer(in.ServerGroupID))
+	out.ManagedServerGroup = (*openstack.ManagedServerGroup)(unsafe.Pointer(in.ManagedServerGroup))
+	out.SchedulerHints = (*openstack.SchedulerHints)(unsafe.Pointer(in.SchedulerHints))
 	out.Networks = *(*[]openstack.OpenStackNetwork)(unsafe.Pointer(&in.Networks))
+	out.FloatingIP = (*openstack.FloatingIP)(unsafe.Pointer(in.FloatingIP))
+	out.DataVolumes = *(*[]openstack.DataVolume)(unsafe.Pointer(&in.DataVolumes))
+	out.Timeouts = (*openstack.Timeouts)(unsafe.Pointer(in.Timeouts))
 	return nil
 }
 
@@ -110,9 +293,12 @@ func Convert_v1alpha1_MachineProviderConfigSpec_To_openstack_MachineProviderConf
 func autoConvert_openstack_MachineProviderConfigSpec_To_v1alpha1_MachineProviderConfigSpec(in *openstack.MachineProviderConfigSpec, out *MachineProviderConfigSpec, s conversion.Scope) error {
 	out.ImageID = in.ImageID
 	out.ImageName = in.ImageName
+	out.ImageSelector = (*ImageSelector)(unsafe.Pointer(in.ImageSelector))
 	out.Region = in.Region
 	out.AvailabilityZone = in.AvailabilityZone
+	out.FallbackAvailabilityZones = *(*[]string)(unsafe.Pointer(&in.FallbackAvailabilityZones))
 	out.FlavorName = in.FlavorName
+	out.FallbackFlavorNames = *(*[]string)(unsafe.Pointer(&in.FallbackFlavorNames))
 	out.KeyName = in.KeyName
 	out.SecurityGroups = *(*[]string)(unsafe.Pointer(&in.SecurityGroups))
 	out.Tags = *(*map[string]string)(unsafe.Pointer(&in.Tags))
@@ -124,7 +310,12 @@ func autoConvert_openstack_MachineProviderConfigSpec_To_v1alpha1_MachineProvider
 	out.RootDiskType = (*string)(unsafe.Pointer(in.RootDiskType))
 	out.UseConfigDrive = (*bool)(unsafe.Pointer(in.UseConfigDrive))
 	out.ServerGroupID = (*string)(unsafe.Pointer(in.ServerGroupID))
+	out.ManagedServerGroup = (*ManagedServerGroup)(unsafe.Pointer(in.ManagedServerGroup))
+	out.SchedulerHints = (*SchedulerHints)(unsafe.Pointer(in.SchedulerHints))
 	out.Networks = *(*[]OpenStackNetwork)(unsafe.Pointer(&in.Networks))
+	out.FloatingIP = (*FloatingIP)(unsafe.Pointer(in.FloatingIP))
+	out.DataVolumes = *(*[]DataVolume)(unsafe.Pointer(&in.DataVolumes))
+	out.Timeouts = (*Timeouts)(unsafe.Pointer(in.Timeouts))
 	return nil
 }
 
@@ -156,3 +347,81 @@ func autoConvert_openstack_OpenStackNetwork_To_v1alpha1_OpenStackNetwork(in *ope
 func Convert_openstack_OpenStackNetwork_To_v1alpha1_OpenStackNetwork(in *openstack.OpenStackNetwork, out *OpenStackNetwork, s conversion.Scope) error {
 	return autoConvert_openstack_OpenStackNetwork_To_v1alpha1_OpenStackNetwork(in, out, s)
 }
+
+func autoConvert_v1alpha1_SchedulerHints_To_openstack_SchedulerHints(in *SchedulerHints, out *openstack.SchedulerHints, s conversion.Scope) error {
+	out.DifferentHost = *(*[]openstack.ServerReference)(unsafe.Pointer(&in.DifferentHost))
+	out.SameHost = *(*[]openstack.ServerReference)(unsafe.Pointer(&in.SameHost))
+	out.BuildNearHostIP = (*string)(unsafe.Pointer(in.BuildNearHostIP))
+	out.Query = (*string)(unsafe.Pointer(in.Query))
+	out.TargetCell = (*string)(unsafe.Pointer(in.TargetCell))
+	out.AdditionalProperties = *(*map[string]string)(unsafe.Pointer(&in.AdditionalProperties))
+	return nil
+}
+
+// Convert_v1alpha1_SchedulerHints_To_openstack_SchedulerHints is an autogenerated conversion function.
+func Convert_v1alpha1_SchedulerHints_To_openstack_SchedulerHints(in *SchedulerHints, out *openstack.SchedulerHints, s conversion.Scope) error {
+	return autoConvert_v1alpha1_SchedulerHints_To_openstack_SchedulerHints(in, out, s)
+}
+
+func autoConvert_openstack_SchedulerHints_To_v1alpha1_SchedulerHints(in *openstack.SchedulerHints, out *SchedulerHints, s conversion.Scope) error {
+	out.DifferentHost = *(*[]ServerReference)(unsafe.Pointer(&in.DifferentHost))
+	out.SameHost = *(*[]ServerReference)(unsafe.Pointer(&in.SameHost))
+	out.BuildNearHostIP = (*string)(unsafe.Pointer(in.BuildNearHostIP))
+	out.Query = (*string)(unsafe.Pointer(in.Query))
+	out.TargetCell = (*string)(unsafe.Pointer(in.TargetCell))
+	out.AdditionalProperties = *(*map[string]string)(unsafe.Pointer(&in.AdditionalProperties))
+	return nil
+}
+
+// Convert_openstack_SchedulerHints_To_v1alpha1_SchedulerHints is an autogenerated conversion function.
+func Convert_openstack_SchedulerHints_To_v1alpha1_SchedulerHints(in *openstack.SchedulerHints, out *SchedulerHints, s conversion.Scope) error {
+	return autoConvert_openstack_SchedulerHints_To_v1alpha1_SchedulerHints(in, out, s)
+}
+
+func autoConvert_v1alpha1_ServerReference_To_openstack_ServerReference(in *ServerReference, out *openstack.ServerReference, s conversion.Scope) error {
+	out.ID = in.ID
+	out.Name = in.Name
+	return nil
+}
+
+// Convert_v1alpha1_ServerReference_To_openstack_ServerReference is an autogenerated conversion function.
+func Convert_v1alpha1_ServerReference_To_openstack_ServerReference(in *ServerReference, out *openstack.ServerReference, s conversion.Scope) error {
+	return autoConvert_v1alpha1_ServerReference_To_openstack_ServerReference(in, out, s)
+}
+
+func autoConvert_openstack_ServerReference_To_v1alpha1_ServerReference(in *openstack.ServerReference, out *ServerReference, s conversion.Scope) error {
+	out.ID = in.ID
+	out.Name = in.Name
+	return nil
+}
+
+// Convert_openstack_ServerReference_To_v1alpha1_ServerReference is an autogenerated conversion function.
+func Convert_openstack_ServerReference_To_v1alpha1_ServerReference(in *openstack.ServerReference, out *ServerReference, s conversion.Scope) error {
+	return autoConvert_openstack_ServerReference_To_v1alpha1_ServerReference(in, out, s)
+}
+
+func autoConvert_v1alpha1_Timeouts_To_openstack_Timeouts(in *Timeouts, out *openstack.Timeouts, s conversion.Scope) error {
+	out.ServerCreate = (*v1.Duration)(unsafe.Pointer(in.ServerCreate))
+	out.VolumeCreate = (*v1.Duration)(unsafe.Pointer(in.VolumeCreate))
+	out.Delete = (*v1.Duration)(unsafe.Pointer(in.Delete))
+	out.PollInterval = (*v1.Duration)(unsafe.Pointer(in.PollInterval))
+	return nil
+}
+
+// Convert_v1alpha1_Timeouts_To_openstack_Timeouts is an autogenerated conversion function.
+func Convert_v1alpha1_Timeouts_To_openstack_Timeouts(in *Timeouts, out *openstack.Timeouts, s conversion.Scope) error {
+	return autoConvert_v1alpha1_Timeouts_To_openstack_Timeouts(in, out, s)
+}
+
+func autoConvert_openstack_Timeouts_To_v1alpha1_Timeouts(in *openstack.Timeouts, out *Timeouts, s conversion.Scope) error {
+	out.ServerCreate = (*v1.Duration)(unsafe.Pointer(in.ServerCreate))
+	out.VolumeCreate = (*v1.Duration)(unsafe.Pointer(in.VolumeCreate))
+	out.Delete = (*v1.Duration)(unsafe.Pointer(in.Delete))
+	out.PollInterval = (*v1.Duration)(unsafe.Pointer(in.PollInterval))
+	return nil
+}
+
+// Convert_openstack_Timeouts_To_v1alpha1_Timeouts is an autogenerated conversion function.
+func Convert_openstack_Timeouts_To_v1alpha1_Timeouts(in *openstack.Timeouts, out *Timeouts, s conversion.Scope) error {
+	return autoConvert_openstack_Timeouts_To_v1alpha1_Timeouts(in, out, s)
+}
@@ -6,16 +6,52 @@
 package validation
 
 import (
+	"encoding/json"
 	"fmt"
+	"net"
+	"net/url"
+	"regexp"
 	"strings"
 
+	"github.com/Masterminds/semver/v3"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/yaml"
 
 	. "github.com/gardener/machine-controller-manager-provider-openstack/pkg/apis/cloudprovider"
 	"github.com/gardener/machine-controller-manager-provider-openstack/pkg/apis/openstack"
 )
 
+var (
+	// supportedServerGroupPolicies are the scheduling policies of Nova server groups.
+	supportedServerGroupPolicies = sets.New("affinity", "anti-affinity", "soft-affinity", "soft-anti-affinity")
+	// reservedSchedulerHints are the scheduler hints that are set from dedicated fields of the provider spec and must not
+	// be overridden by additional properties.
+	reservedSchedulerHints = sets.New("group", "different_host", "same_host", "build_near_host_ip", "cidr", "query", "target_cell")
+	// uuidRegexp matches the IDs of OpenStack resources like servers.
+	uuidRegexp = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	// supportedEndpointInterfaces are the interfaces of the endpoints in the service catalog.
+	supportedEndpointInterfaces = sets.New("public", "internal", "admin")
+	// supportedProxySchemes are the schemes of the proxy URLs supported by the HTTP client.
+	supportedProxySchemes = sets.New("http", "https", "socks5")
+	// cloudsYAMLConflictingKeys are the secret keys holding credentials, which are given by the clouds.yaml file instead.
+	cloudsYAMLConflictingKeys = []string{
+		OpenStackAuthURL, OpenStackUsername, OpenStackPassword, OpenStackDomainName, OpenStackDomainID, OpenStackTenantName,
+		OpenStackTenantID, OpenStackUserDomainName, OpenStackUserDomainID, OpenStackApplicationCredentialID,
+		OpenStackApplicationCredentialName, OpenStackApplicationCredentialSecret, OpenStackIdentityProvider, OpenStackProtocol,
+		OpenStackAccessToken, OpenStackAccessTokenFile, OpenStackNextApplicationCredentialID, OpenStackNextApplicationCredentialName,
+		OpenStackNextApplicationCredentialSecret,
+	}
+	// oidcConflictingKeys are the secret keys holding static credentials, which are replaced by the OIDC access token.
+	oidcConflictingKeys = []string{
+		OpenStackUsername, OpenStackPassword, OpenStackUserDomainName, OpenStackUserDomainID, OpenStackApplicationCredentialID,
+		OpenStackApplicationCredentialName, OpenStackApplicationCredentialSecret, OpenStackNextApplicationCredentialID,
+		OpenStackNextApplicationCredentialName, OpenStackNextApplicationCredentialSecret,
+	}
+)
+
 // ValidateRequest validates a request received by the OpenStack driver.
 func ValidateRequest(providerConfig *openstack.MachineProviderConfig, secret *corev1.Secret) error {
 	allErrs := field.ErrorList{}
@@ -33,9 +69,14 @@ func validateMachineProviderConfig(providerConfig *openstack.MachineProviderConf
 
 	fldPath := field.NewPath("spec")
 
-	if providerConfig.Spec.ImageID == "" {
+	if providerConfig.Spec.ImageSelector != nil {
+		if providerConfig.Spec.ImageID != "" || providerConfig.Spec.ImageName != "" {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("imageSelector"), "ImageSelector must not be given together with ImageID or ImageName"))
+		}
+		allErrs = append(allErrs, validateImageSelector(providerConfig.Spec.ImageSelector, fldPath.Child("imageSelector"))...)
+	} else if providerConfig.Spec.ImageID == "" {
 		if providerConfig.Spec.ImageName == "" {
-			allErrs = append(allErrs, field.Required(fldPath.Child("imageName"), "ImageName is required if no ImageID is given"))
+			allErrs = append(allErrs, field.Required(fldPath.Child("imageName"), "ImageName is required if no ImageID or ImageSelector is given"))
 		}
 	}
 
@@ -66,6 +107,42 @@ func validateMachineProviderConfig(providerConfig *openstack.MachineProviderConf
 
 	allErrs = append(allErrs, validateNetworks(providerConfig.Spec.Networks, providerConfig.Spec.PodNetworkCidr, providerConfig.Spec.PodNetworkCIDRs, field.NewPath("spec.networks"))...)
 	allErrs = append(allErrs, validateClassSpecTags(providerConfig.Spec.Tags, field.NewPath("spec.tags"))...)
+	if providerConfig.Spec.FloatingIP != nil {
+		allErrs = append(allErrs, validateFloatingIP(providerConfig.Spec.FloatingIP, field.NewPath("spec.floatingIP"))...)
+	}
+	allErrs = append(allErrs, validateFallbackFlavorNames(providerConfig.Spec.FlavorName, providerConfig.Spec.FallbackFlavorNames, field.NewPath("spec.fallbackFlavorNames"))...)
+	allErrs = append(allErrs, validateFallbackAvailabilityZones(providerConfig.Spec.AvailabilityZone, providerConfig.Spec.FallbackAvailabilityZones, field.NewPath("spec.fallbackAvailabilityZones"))...)
+	if providerConfig.Spec.ManagedServerGroup != nil {
+		if providerConfig.Spec.ServerGroupID != nil {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("managedServerGroup"), "ManagedServerGroup must not be given together with ServerGroupID"))
+		}
+		allErrs = append(allErrs, validateManagedServerGroup(providerConfig.Spec.ManagedServerGroup, fldPath.Child("managedServerGroup"))...)
+	}
+	if providerConfig.Spec.SchedulerHints != nil {
+		allErrs = append(allErrs, validateSchedulerHints(providerConfig.Spec.SchedulerHints, fldPath.Child("schedulerHints"))...)
+	}
+	allErrs = append(allErrs, validateDataVolumes(providerConfig.Spec.DataVolumes, field.NewPath("spec.dataVolumes"))...)
+	if providerConfig.Spec.Timeouts != nil {
+		allErrs = append(allErrs, validateTimeouts(providerConfig.Spec.Timeouts, field.NewPath("spec.timeouts"))...)
+	}
+
+	return allErrs
+}
+
+func validateImageSelector(selector *openstack.ImageSelector, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if selector.VersionConstraint != nil {
+		if selector.VersionProperty == nil {
+			allErrs = append(allErrs, field.Required(fldPath.Child("versionProperty"), "versionProperty is required if a versionConstraint is given"))
+		}
+		if _, err := semver.NewConstraint(*selector.VersionConstraint); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("versionConstraint"), *selector.VersionConstraint, err.Error()))
+		}
+	}
+	if selector.VersionProperty != nil && *selector.VersionProperty == "" {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("versionProperty"), *selector.VersionProperty, "versionProperty must not be empty if specified"))
+	}
 
 	return allErrs
 }
@@ -89,6 +166,166 @@ func validateNetworks(networks []openstack.OpenStackNetwork, podNetworkCidr stri
 	return allErrs
 }
 
+func validateFallbackAvailabilityZones(availabilityZone string, fallbackZones []string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	zones := sets.New(availabilityZone)
+
+	for index, zone := range fallbackZones {
+		if zone == "" {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(index), zone, "availability zone must not be empty"))
+		} else if zones.Has(zone) {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Index(index), zone))
+		}
+		zones.Insert(zone)
+	}
+
+	return allErrs
+}
+
+func validateFallbackFlavorNames(flavorName string, fallbackFlavors []string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	flavors := sets.New(flavorName)
+
+	for index, flavor := range fallbackFlavors {
+		if flavor == "" {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(index), flavor, "flavor name must not be empty"))
+		} else if flavors.Has(flavor) {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Index(index), flavor))
+		}
+		flavors.Insert(flavor)
+	}
+
+	return allErrs
+}
+
+func validateFloatingIP(floatingIP *openstack.FloatingIP, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if floatingIP.NetworkID == "" && floatingIP.PoolName == "" {
+		allErrs = append(allErrs, field.Required(fldPath, "at least one of floating IP \"networkID\" or \"poolName\" is required"))
+	}
+	if floatingIP.NetworkID != "" && floatingIP.PoolName != "" {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "simultaneous use of floating IP \"networkID\" and \"poolName\" is forbidden"))
+	}
+	if floatingIP.SubnetID != nil && *floatingIP.SubnetID == "" {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("subnetID"), *floatingIP.SubnetID, "subnetID must not be empty if specified"))
+	}
+
+	return allErrs
+}
+
+func validateManagedServerGroup(serverGroup *openstack.ManagedServerGroup, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if serverGroup.Name == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("name"), "server group \"name\" is required"))
+	}
+	if !supportedServerGroupPolicies.Has(serverGroup.Policy) {
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("policy"), serverGroup.Policy, sets.List(supportedServerGroupPolicies)))
+	}
+
+	return allErrs
+}
+
+func validateSchedulerHints(hints *openstack.SchedulerHints, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	allErrs = append(allErrs, validateServerReferences(hints.DifferentHost, fldPath.Child("differentHost"))...)
+	allErrs = append(allErrs, validateServerReferences(hints.SameHost, fldPath.Child("sameHost"))...)
+	if hints.BuildNearHostIP != nil {
+		if _, _, err := net.ParseCIDR(*hints.BuildNearHostIP); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("buildNearHostIP"), *hints.BuildNearHostIP, "buildNearHostIP must be a valid CIDR"))
+		}
+	}
+	if hints.Query != nil {
+		var query []interface{}
+		if err := json.Unmarshal([]byte(*hints.Query), &query); err != nil || len(query) < 3 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("query"), *hints.Query, "query must be a JSON encoded conditional statement in the format of [op, variable, value]"))
+		}
+	}
+	if hints.TargetCell != nil && *hints.TargetCell == "" {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("targetCell"), *hints.TargetCell, "targetCell must not be empty if specified"))
+	}
+	for _, key := range sets.List(sets.KeySet(hints.AdditionalProperties)) {
+		if key == "" {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("additionalProperties"), key, "scheduler hint must not be empty"))
+		} else if reservedSchedulerHints.Has(key) {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("additionalProperties").Key(key), "scheduler hint must be set with the dedicated field"))
+		}
+	}
+
+	return allErrs
+}
+
+func validateServerReferences(references []openstack.ServerReference, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for index, reference := range references {
+		fldPath := fldPath.Index(index)
+		if reference.ID == "" && reference.Name == "" {
+			allErrs = append(allErrs, field.Required(fldPath, "at least one of server \"id\" or \"name\" is required"))
+		}
+		if reference.ID != "" && reference.Name != "" {
+			allErrs = append(allErrs, field.Forbidden(fldPath, "simultaneous use of server \"id\" and \"name\" is forbidden"))
+		}
+		if reference.ID != "" {
+			allErrs = append(allErrs, validateUUID(reference.ID, fldPath.Child("id"))...)
+		}
+	}
+
+	return allErrs
+}
+
+func validateUUID(id string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if !uuidRegexp.MatchString(id) {
+		allErrs = append(allErrs, field.Invalid(fldPath, id, "must be a valid UUID"))
+	}
+
+	return allErrs
+}
+
+func validateDataVolumes(dataVolumes []openstack.DataVolume, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	names := sets.New[string]()
+
+	for index, dataVolume := range dataVolumes {
+		fldPath := fldPath.Index(index)
+		if dataVolume.Name == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("name"), "data volume \"name\" is required"))
+		} else if names.Has(dataVolume.Name) {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Child("name"), dataVolume.Name))
+		}
+		names.Insert(dataVolume.Name)
+
+		if dataVolume.Size <= 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("size"), dataVolume.Size, "data volume \"size\" must be positive"))
+		}
+		if dataVolume.Type != nil && *dataVolume.Type == "" {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("type"), *dataVolume.Type, "type must not be empty if specified"))
+		}
+	}
+
+	return allErrs
+}
+
+func validateTimeouts(timeouts *openstack.Timeouts, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	validateDuration := func(duration *metav1.Duration, fldPath *field.Path) {
+		if duration != nil && duration.Duration <= 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath, duration.Duration.String(), "duration must be positive"))
+		}
+	}
+	validateDuration(timeouts.ServerCreate, fldPath.Child("serverCreate"))
+	validateDuration(timeouts.VolumeCreate, fldPath.Child("volumeCreate"))
+	validateDuration(timeouts.Delete, fldPath.Child("delete"))
+	validateDuration(timeouts.PollInterval, fldPath.Child("pollInterval"))
+
+	return allErrs
+}
+
 func validateClassSpecTags(tags map[string]string, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 	clusterName := ""
@@ -118,11 +355,71 @@ func validateSecret(secret *corev1.Secret) field.ErrorList {
 
 	root := field.NewPath("data")
 	data := secret.Data
+	if len(data[OpenStackCloudsYAML]) != 0 {
+		allErrs = append(allErrs, validateCloudsYAMLCredentials(data, root)...)
+	} else {
+		allErrs = append(allErrs, validateCredentials(data, root)...)
+	}
+
+	if endpointInterface := strings.TrimSpace(string(data[OpenStackEndpointInterface])); endpointInterface != "" && !supportedEndpointInterfaces.Has(endpointInterface) {
+		allErrs = append(allErrs, field.NotSupported(root.Key(OpenStackEndpointInterface), endpointInterface, sets.List(supportedEndpointInterfaces)))
+	}
+	for _, key := range []string{OpenStackComputeEndpoint, OpenStackNetworkEndpoint, OpenStackVolumeEndpoint, OpenStackImageEndpoint} {
+		if endpoint := strings.TrimSpace(string(data[key])); endpoint != "" {
+			if u, err := url.Parse(endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				allErrs = append(allErrs, field.Invalid(root.Key(key), endpoint, "must be an absolute http or https URL"))
+			}
+		}
+	}
+
+	if len(data[OpenStackClientCert]) != 0 && len(data[OpenStackClientKey]) == 0 {
+		allErrs = append(allErrs, field.Required(root.Key(OpenStackClientKey), fmt.Sprintf("%s is required, if %s is present", OpenStackClientKey, OpenStackClientCert)))
+	}
+
+	for _, key := range []string{OpenStackInsecure, OpenStackCACertAppend} {
+		if value, ok := data[key]; ok {
+			switch string(value) {
+			case "true":
+			case "false":
+			default:
+				allErrs = append(allErrs, field.Invalid(root.Key(key), string(value), "value does not match expected boolean value [\"true\"|\"false\"]"))
+			}
+		}
+	}
+
+	if proxyURL := strings.TrimSpace(string(data[OpenStackProxyURL])); proxyURL != "" {
+		if u, err := url.Parse(proxyURL); err != nil || !supportedProxySchemes.Has(u.Scheme) || u.Host == "" {
+			allErrs = append(allErrs, field.Invalid(root.Key(OpenStackProxyURL), proxyURL, fmt.Sprintf("must be an absolute URL with one of the schemes %v", sets.List(supportedProxySchemes))))
+		}
+	}
+
+	return allErrs
+}
+
+// validateCredentials validates the credentials given as individual keys of the secret.
+func validateCredentials(data map[string][]byte, root *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for _, key := range []string{OpenStackSecureYAML, OpenStackCloud} {
+		if len(data[key]) != 0 {
+			allErrs = append(allErrs, field.Forbidden(root.Key(key), fmt.Sprintf("%s is only allowed together with %s", key, OpenStackCloudsYAML)))
+		}
+	}
+	if isEmptyStringByteSlice(data[OpenStackIdentityProvider]) {
+		for _, key := range []string{OpenStackProtocol, OpenStackAccessToken, OpenStackAccessTokenFile} {
+			if len(data[key]) != 0 {
+				allErrs = append(allErrs, field.Forbidden(root.Key(key), fmt.Sprintf("%s is only allowed together with %s", key, OpenStackIdentityProvider)))
+			}
+		}
+	}
+
 	if isEmptyStringByteSlice(data[OpenStackAuthURL]) {
 		allErrs = append(allErrs, field.Required(root.Key(OpenStackAuthURL), fmt.Sprintf("%s is required", OpenStackAuthURL)))
 	}
 
-	if !isEmptyStringByteSlice(data[OpenStackPassword]) {
+	if !isEmptyStringByteSlice(data[OpenStackIdentityProvider]) {
+		allErrs = append(allErrs, validateOIDCCredentials(data, root)...)
+	} else if !isEmptyStringByteSlice(data[OpenStackPassword]) {
 		if !isEmptyStringByteSlice(data[OpenStackApplicationCredentialSecret]) {
 			msg := fmt.Sprintf("cannot specify both '%s' and '%s'", OpenStackPassword, OpenStackApplicationCredentialSecret)
 			allErrs = append(allErrs, field.Forbidden(root.Key(OpenStackPassword), msg))
@@ -149,6 +446,10 @@ func validateSecret(secret *corev1.Secret) field.ErrorList {
 		}
 	}
 
+	if isEmptyStringByteSlice(data[OpenStackIdentityProvider]) {
+		allErrs = append(allErrs, validateNextApplicationCredential(data, root)...)
+	}
+
 	if isEmptyStringByteSlice(data[OpenStackDomainName]) && isEmptyStringByteSlice(data[OpenStackDomainID]) {
 		allErrs = append(allErrs, field.Required(root.Key(OpenStackDomainName), fmt.Sprintf("one of the following keys is required [%s|%s]", OpenStackDomainName, OpenStackDomainID)))
 	}
@@ -157,22 +458,106 @@ func validateSecret(secret *corev1.Secret) field.ErrorList {
 		allErrs = append(allErrs, field.Required(root.Key(OpenStackTenantName), fmt.Sprintf("one of the following keys is required [%s|%s]", OpenStackTenantName, OpenStackTenantID)))
 	}
 
-	if len(data[OpenStackClientCert]) != 0 && len(data[OpenStackClientKey]) == 0 {
-		allErrs = append(allErrs, field.Required(root.Key(OpenStackClientKey), fmt.Sprintf("%s is required, if %s is present", OpenStackClientKey, OpenStackClientCert)))
+	return allErrs
+}
+
+// validateNextApplicationCredential validates the application credential, which replaces the current credentials during
+// a rotation. It is optional, but has to be complete if any of its keys is given.
+func validateNextApplicationCredential(data map[string][]byte, root *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	hasID, hasName := !isEmptyStringByteSlice(data[OpenStackNextApplicationCredentialID]), !isEmptyStringByteSlice(data[OpenStackNextApplicationCredentialName])
+	if isEmptyStringByteSlice(data[OpenStackNextApplicationCredentialSecret]) {
+		if hasID || hasName {
+			allErrs = append(allErrs, field.Required(root.Key(OpenStackNextApplicationCredentialSecret), fmt.Sprintf("%s is required if %s or %s present", OpenStackNextApplicationCredentialSecret, OpenStackNextApplicationCredentialID, OpenStackNextApplicationCredentialName)))
+		}
+		return allErrs
 	}
 
-	if insecureStr, ok := data[OpenStackInsecure]; ok {
-		switch string(insecureStr) {
-		case "true":
-		case "false":
-		default:
-			allErrs = append(allErrs, field.Invalid(root.Key(OpenStackInsecure), string(insecureStr), "value does not match expected boolean value [\"true\"|\"false\"]"))
+	if !hasID && (!hasName || isEmptyStringByteSlice(data[OpenStackUsername])) {
+		msg := fmt.Sprintf("%s or %s and %s are required if %s present", OpenStackNextApplicationCredentialID, OpenStackNextApplicationCredentialName, OpenStackUsername, OpenStackNextApplicationCredentialSecret)
+		allErrs = append(allErrs, field.Required(root.Key(OpenStackNextApplicationCredentialID), msg))
+		if !hasName {
+			allErrs = append(allErrs, field.Required(root.Key(OpenStackNextApplicationCredentialName), msg))
+		}
+		if isEmptyStringByteSlice(data[OpenStackUsername]) {
+			allErrs = append(allErrs, field.Required(root.Key(OpenStackUsername), msg))
 		}
 	}
 
 	return allErrs
 }
 
+// validateOIDCCredentials validates the credentials for the exchange of an OIDC access token at a Keystone identity
+// provider. Static credentials must not be set in addition.
+func validateOIDCCredentials(data map[string][]byte, root *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	hasAccessToken, hasAccessTokenFile := !isEmptyStringByteSlice(data[OpenStackAccessToken]), !isEmptyStringByteSlice(data[OpenStackAccessTokenFile])
+	if hasAccessToken && hasAccessTokenFile {
+		msg := fmt.Sprintf("cannot specify both '%s' and '%s'", OpenStackAccessToken, OpenStackAccessTokenFile)
+		allErrs = append(allErrs, field.Forbidden(root.Key(OpenStackAccessToken), msg))
+		allErrs = append(allErrs, field.Forbidden(root.Key(OpenStackAccessTokenFile), msg))
+	} else if !hasAccessToken && !hasAccessTokenFile {
+		msg := fmt.Sprintf("must either specify '%s' or '%s' if '%s' is given", OpenStackAccessToken, OpenStackAccessTokenFile, OpenStackIdentityProvider)
+		allErrs = append(allErrs, field.Required(root.Key(OpenStackAccessToken), msg))
+		allErrs = append(allErrs, field.Required(root.Key(OpenStackAccessTokenFile), msg))
+	}
+
+	for _, key := range oidcConflictingKeys {
+		if len(data[key]) != 0 {
+			allErrs = append(allErrs, field.Forbidden(root.Key(key), fmt.Sprintf("cannot specify both '%s' and '%s'", key, OpenStackIdentityProvider)))
+		}
+	}
+
+	return allErrs
+}
+
+// validateCloudsYAMLCredentials validates the credentials given as clouds.yaml file. The individual credential keys
+// must not be set in addition, since it would be ambiguous which credentials are used.
+func validateCloudsYAMLCredentials(data map[string][]byte, root *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for _, key := range cloudsYAMLConflictingKeys {
+		if len(data[key]) != 0 {
+			allErrs = append(allErrs, field.Forbidden(root.Key(key), fmt.Sprintf("cannot specify both '%s' and '%s'", key, OpenStackCloudsYAML)))
+		}
+	}
+
+	cloud := strings.TrimSpace(string(data[OpenStackCloud]))
+	if cloud == "" {
+		allErrs = append(allErrs, field.Required(root.Key(OpenStackCloud), fmt.Sprintf("%s is required if '%s' is given", OpenStackCloud, OpenStackCloudsYAML)))
+	}
+
+	clouds, err := unmarshalCloudNames(data[OpenStackCloudsYAML])
+	if err != nil {
+		return append(allErrs, field.Invalid(root.Key(OpenStackCloudsYAML), "(hidden)", err.Error()))
+	}
+	if len(data[OpenStackSecureYAML]) != 0 {
+		secureClouds, err := unmarshalCloudNames(data[OpenStackSecureYAML])
+		if err != nil {
+			return append(allErrs, field.Invalid(root.Key(OpenStackSecureYAML), "(hidden)", err.Error()))
+		}
+		clouds = clouds.Union(secureClouds)
+	}
+	if cloud != "" && !clouds.Has(cloud) {
+		allErrs = append(allErrs, field.Invalid(root.Key(OpenStackCloud), cloud, fmt.Sprintf("cloud is not defined in %s", OpenStackCloudsYAML)))
+	}
+
+	return allErrs
+}
+
+// unmarshalCloudNames returns the names of the clouds defined in a clouds.yaml or secure.yaml file.
+func unmarshalCloudNames(data []byte) (sets.Set[string], error) {
+	var clouds struct {
+		Clouds map[string]json.RawMessage `json:"clouds"`
+	}
+	if err := yaml.Unmarshal(data, &clouds); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal yaml: %w", err)
+	}
+	return sets.KeySet(clouds.Clouds), nil
+}
+
 // validateUserData validates that a secret contains user data.
 func validateUserData(secret *corev1.Secret) field.ErrorList {
 	allErrs := field.ErrorList{}
@@ -6,11 +6,14 @@ package validation
 
 import (
 	"fmt"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/gstruct"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 
 	. "github.com/gardener/machine-controller-manager-provider-openstack/pkg/apis/cloudprovider"
 	api "github.com/gardener/machine-controller-manager-provider-openstack/pkg/apis/openstack"
@@ -140,6 +143,234 @@ var _ = Describe("Validation", func() {
 			})
 		})
 
+		Context("#ImageSelector", func() {
+			It("should accept an image selector instead of an image ID or name", func() {
+				machineProviderConfig.Spec.ImageID = ""
+				machineProviderConfig.Spec.ImageName = ""
+				machineProviderConfig.Spec.ImageSelector = &api.ImageSelector{
+					Properties:        map[string]string{"os_distro": "gardenlinux"},
+					VersionProperty:   ptr.To("os_version"),
+					VersionConstraint: ptr.To(">= 1.2, < 2"),
+				}
+
+				err := validateMachineProviderConfig(machineProviderConfig).ToAggregate()
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("should fail if the selector is combined with an image name or has an invalid version constraint", func() {
+				machineProviderConfig.Spec.ImageSelector = &api.ImageSelector{
+					VersionConstraint: ptr.To("not-a-constraint"),
+				}
+
+				err := validateMachineProviderConfig(machineProviderConfig)
+				Expect(err).To(ConsistOf(
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Type":  BeEquivalentTo("FieldValueForbidden"),
+						"Field": Equal("spec.imageSelector"),
+					})),
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Type":  BeEquivalentTo("FieldValueRequired"),
+						"Field": Equal("spec.imageSelector.versionProperty"),
+					})),
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Type":  BeEquivalentTo("FieldValueInvalid"),
+						"Field": Equal("spec.imageSelector.versionConstraint"),
+					})),
+				))
+			})
+		})
+
+		Context("#FallbackAvailabilityZones", func() {
+			It("should fail if a fallback zone repeats a previous zone", func() {
+				machineProviderConfig.Spec.FallbackAvailabilityZones = []string{"zone-b", machineProviderConfig.Spec.AvailabilityZone}
+
+				err := validateMachineProviderConfig(machineProviderConfig)
+				Expect(err).To(ConsistOf(
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Type":  BeEquivalentTo("FieldValueDuplicate"),
+						"Field": Equal("spec.fallbackAvailabilityZones[1]"),
+					})),
+				))
+			})
+		})
+
+		Context("#FallbackFlavorNames", func() {
+			It("should fail for empty fallback flavors", func() {
+				machineProviderConfig.Spec.FallbackFlavorNames = []string{"large", ""}
+
+				err := validateMachineProviderConfig(machineProviderConfig)
+				Expect(err).To(ConsistOf(
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Type":  BeEquivalentTo("FieldValueInvalid"),
+						"Field": Equal("spec.fallbackFlavorNames[1]"),
+					})),
+				))
+			})
+		})
+
+		Context("#ManagedServerGroup", func() {
+			It("should accept a managed server group", func() {
+				machineProviderConfig.Spec.ManagedServerGroup = &api.ManagedServerGroup{Name: "workers", Policy: "soft-anti-affinity"}
+
+				err := validateMachineProviderConfig(machineProviderConfig).ToAggregate()
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("should fail for an unsupported policy", func() {
+				machineProviderConfig.Spec.ManagedServerGroup = &api.ManagedServerGroup{Name: "workers", Policy: "spread"}
+
+				err := validateMachineProviderConfig(machineProviderConfig)
+				Expect(err).To(ConsistOf(
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Type":  BeEquivalentTo("FieldValueNotSupported"),
+						"Field": Equal("spec.managedServerGroup.policy"),
+					})),
+				))
+			})
+
+			It("should fail if a server group ID is given as well", func() {
+				machineProviderConfig.Spec.ServerGroupID = ptr.To("id")
+				machineProviderConfig.Spec.ManagedServerGroup = &api.ManagedServerGroup{Name: "workers", Policy: "affinity"}
+
+				err := validateMachineProviderConfig(machineProviderConfig)
+				Expect(err).To(ConsistOf(
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Type":  BeEquivalentTo("FieldValueForbidden"),
+						"Field": Equal("spec.managedServerGroup"),
+					})),
+				))
+			})
+		})
+
+		Context("#SchedulerHints", func() {
+			It("should accept valid scheduler hints", func() {
+				machineProviderConfig.Spec.SchedulerHints = &api.SchedulerHints{
+					DifferentHost:        []api.ServerReference{{Name: "etcd"}},
+					BuildNearHostIP:      ptr.To("192.168.1.1/24"),
+					Query:                ptr.To(`["and", [">=", "$free_ram_mb", 1024], [">=", "$free_disk_mb", 204800]]`),
+					AdditionalProperties: map[string]string{"reservation": "foo"},
+				}
+
+				err := validateMachineProviderConfig(machineProviderConfig).ToAggregate()
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("should fail for server IDs which are no UUIDs", func() {
+				machineProviderConfig.Spec.SchedulerHints = &api.SchedulerHints{DifferentHost: []api.ServerReference{{ID: "etcd"}}}
+
+				err := validateMachineProviderConfig(machineProviderConfig)
+				Expect(err).To(ConsistOf(
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Type":  BeEquivalentTo("FieldValueInvalid"),
+						"Field": Equal("spec.schedulerHints.differentHost[0].id"),
+					})),
+				))
+			})
+			It("should fail for invalid scheduler hints", func() {
+				machineProviderConfig.Spec.SchedulerHints = &api.SchedulerHints{
+					SameHost:             []api.ServerReference{{ID: "c6a7b1a2-3d4e-4f50-8a6b-7c8d9e0f1a2b", Name: "etcd"}},
+					BuildNearHostIP:      ptr.To("192.168.1.1"),
+					Query:                ptr.To(`[">=", "$free_ram_mb"]`),
+					AdditionalProperties: map[string]string{"group": "foo"},
+				}
+
+				err := validateMachineProviderConfig(machineProviderConfig)
+				Expect(err).To(ConsistOf(
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Type":  BeEquivalentTo("FieldValueForbidden"),
+						"Field": Equal("spec.schedulerHints.sameHost[0]"),
+					})),
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Type":  BeEquivalentTo("FieldValueInvalid"),
+						"Field": Equal("spec.schedulerHints.buildNearHostIP"),
+					})),
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Type":  BeEquivalentTo("FieldValueInvalid"),
+						"Field": Equal("spec.schedulerHints.query"),
+					})),
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Type":  BeEquivalentTo("FieldValueForbidden"),
+						"Field": Equal("spec.schedulerHints.additionalProperties[group]"),
+					})),
+				))
+			})
+		})
+
+		Context("#FloatingIP", func() {
+			It("should accept a floating IP pool", func() {
+				machineProviderConfig.Spec.FloatingIP = &api.FloatingIP{PoolName: "public"}
+
+				err := validateMachineProviderConfig(machineProviderConfig).ToAggregate()
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("should fail if neither network ID nor pool name are set", func() {
+				machineProviderConfig.Spec.FloatingIP = &api.FloatingIP{}
+
+				err := validateMachineProviderConfig(machineProviderConfig)
+				Expect(err).To(ConsistOf(
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Type":  BeEquivalentTo("FieldValueRequired"),
+						"Field": Equal("spec.floatingIP"),
+					})),
+				))
+			})
+
+			It("should fail if both network ID and pool name are set", func() {
+				machineProviderConfig.Spec.FloatingIP = &api.FloatingIP{NetworkID: "id", PoolName: "public"}
+
+				err := validateMachineProviderConfig(machineProviderConfig)
+				Expect(err).To(ConsistOf(
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Type":  BeEquivalentTo("FieldValueForbidden"),
+						"Field": Equal("spec.floatingIP"),
+					})),
+				))
+			})
+		})
+
+		Context("#DataVolumes", func() {
+			It("should accept data volumes", func() {
+				machineProviderConfig.Spec.DataVolumes = []api.DataVolume{{Name: "etcd", Size: 10}, {Name: "containerd", Size: 50}}
+
+				err := validateMachineProviderConfig(machineProviderConfig).ToAggregate()
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("should fail for duplicate names and invalid sizes", func() {
+				machineProviderConfig.Spec.DataVolumes = []api.DataVolume{{Name: "etcd", Size: 10}, {Name: "etcd", Size: 0}}
+
+				err := validateMachineProviderConfig(machineProviderConfig)
+				Expect(err).To(ConsistOf(
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Type":  BeEquivalentTo("FieldValueDuplicate"),
+						"Field": Equal("spec.dataVolumes[1].name"),
+					})),
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Type":  BeEquivalentTo("FieldValueInvalid"),
+						"Field": Equal("spec.dataVolumes[1].size"),
+					})),
+				))
+			})
+		})
+
+		Context("#Timeouts", func() {
+			It("should fail for non-positive durations", func() {
+				machineProviderConfig.Spec.Timeouts = &api.Timeouts{
+					ServerCreate: &metav1.Duration{Duration: time.Hour},
+					PollInterval: &metav1.Duration{},
+				}
+
+				err := validateMachineProviderConfig(machineProviderConfig)
+				Expect(err).To(ConsistOf(
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Type":  BeEquivalentTo("FieldValueInvalid"),
+						"Field": Equal("spec.timeouts.pollInterval"),
+					})),
+				))
+			})
+		})
+
 		Context("#Tags", func() {
 			It("should return an error if the cluster tags are missing", func() {
 				spec := &machineProviderConfig.Spec
@@ -268,12 +499,268 @@ var _ = Describe("Validation", func() {
 			Expect(err).ToNot(HaveOccurred())
 		})
 
+		It("should accept the next application credential", func() {
+			secret.Data[OpenStackNextApplicationCredentialID] = []byte("next-app-id")
+			secret.Data[OpenStackNextApplicationCredentialSecret] = []byte("next-app-secret")
+
+			err := validateSecret(secret).ToAggregate()
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should fail if the next application credential is incomplete", func() {
+			delete(secret.Data, OpenStackUsername)
+			delete(secret.Data, OpenStackPassword)
+			secret.Data[OpenStackApplicationCredentialID] = []byte("app-id")
+			secret.Data[OpenStackApplicationCredentialSecret] = []byte("app-secret")
+			secret.Data[OpenStackNextApplicationCredentialName] = []byte("next-app-name")
+			secret.Data[OpenStackNextApplicationCredentialSecret] = []byte("next-app-secret")
+
+			err := validateSecret(secret)
+			Expect(err).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  BeEquivalentTo("FieldValueRequired"),
+					"Field": Equal("data[applicationCredentialID.next]"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  BeEquivalentTo("FieldValueRequired"),
+					"Field": Equal("data[username]"),
+				})),
+			))
+		})
+
+		It("should fail if the next application credential has no secret", func() {
+			secret.Data[OpenStackNextApplicationCredentialID] = []byte("next-app-id")
+
+			err := validateSecret(secret)
+			Expect(err).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  BeEquivalentTo("FieldValueRequired"),
+					"Field": Equal("data[applicationCredentialSecret.next]"),
+				})),
+			))
+		})
+
 		It("should fail if Insecure has erroneous value", func() {
 			secret.Data[OpenStackInsecure] = []byte("foo")
 
 			err := validateSecret(secret).ToAggregate()
 			Expect(err).To(HaveOccurred())
 		})
+
+		It("should accept the endpoint interface and overrides", func() {
+			secret.Data[OpenStackEndpointInterface] = []byte("internal")
+			secret.Data[OpenStackVolumeEndpoint] = []byte("https://cinder.example.com:8776/v3/project")
+
+			err := validateSecret(secret).ToAggregate()
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should fail if the endpoint interface or overrides are invalid", func() {
+			secret.Data[OpenStackEndpointInterface] = []byte("private")
+			secret.Data[OpenStackComputeEndpoint] = []byte("nova.example.com")
+
+			err := validateSecret(secret)
+			Expect(err).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  BeEquivalentTo("FieldValueNotSupported"),
+					"Field": Equal("data[endpointInterface]"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  BeEquivalentTo("FieldValueInvalid"),
+					"Field": Equal("data[computeEndpoint]"),
+				})),
+			))
+		})
+
+		It("should accept the CA certificate append flag and the proxy URL", func() {
+			secret.Data[OpenStackCACertAppend] = []byte("true")
+			secret.Data[OpenStackProxyURL] = []byte("http://proxy.example.com:3128")
+
+			err := validateSecret(secret).ToAggregate()
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should fail if the CA certificate append flag or the proxy URL are invalid", func() {
+			secret.Data[OpenStackCACertAppend] = []byte("yes")
+			secret.Data[OpenStackProxyURL] = []byte("ftp://proxy.example.com")
+
+			err := validateSecret(secret)
+			Expect(err).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  BeEquivalentTo("FieldValueInvalid"),
+					"Field": Equal("data[caCertAppend]"),
+				})),
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  BeEquivalentTo("FieldValueInvalid"),
+					"Field": Equal("data[proxyURL]"),
+				})),
+			))
+		})
+
+		It("should fail if a cloud is selected without clouds.yaml", func() {
+			secret.Data[OpenStackCloud] = []byte("openstack")
+
+			err := validateSecret(secret)
+			Expect(err).To(ConsistOf(
+				PointTo(MatchFields(IgnoreExtras, Fields{
+					"Type":  BeEquivalentTo("FieldValueForbidden"),
+					"Field": Equal("data[cloud]"),
+				})),
+			))
+		})
+
+		Context("with an identity provider", func() {
+			BeforeEach(func() {
+				delete(secret.Data, OpenStackUsername)
+				delete(secret.Data, OpenStackPassword)
+				secret.Data[OpenStackIdentityProvider] = []byte("kubernetes")
+				secret.Data[OpenStackAccessTokenFile] = []byte("/var/run/secrets/openstack/token")
+			})
+
+			It("should not fail", func() {
+				err := validateSecret(secret).ToAggregate()
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("should fail if no access token is given", func() {
+				delete(secret.Data, OpenStackAccessTokenFile)
+
+				err := validateSecret(secret)
+				Expect(err).To(ConsistOf(
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Type":  BeEquivalentTo("FieldValueRequired"),
+						"Field": Equal("data[accessToken]"),
+					})),
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Type":  BeEquivalentTo("FieldValueRequired"),
+						"Field": Equal("data[accessTokenFile]"),
+					})),
+				))
+			})
+
+			It("should fail if both an access token and a file are given", func() {
+				secret.Data[OpenStackAccessToken] = []byte("token")
+
+				err := validateSecret(secret)
+				Expect(err).To(ConsistOf(
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Type":  BeEquivalentTo("FieldValueForbidden"),
+						"Field": Equal("data[accessToken]"),
+					})),
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Type":  BeEquivalentTo("FieldValueForbidden"),
+						"Field": Equal("data[accessTokenFile]"),
+					})),
+				))
+			})
+
+			It("should fail if static credentials are given in addition", func() {
+				secret.Data[OpenStackApplicationCredentialSecret] = []byte("app-secret")
+
+				err := validateSecret(secret)
+				Expect(err).To(ConsistOf(
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Type":  BeEquivalentTo("FieldValueForbidden"),
+						"Field": Equal("data[applicationCredentialSecret]"),
+					})),
+				))
+			})
+
+			It("should fail if an access token is given without identity provider", func() {
+				delete(secret.Data, OpenStackIdentityProvider)
+				secret.Data[OpenStackPassword] = []byte("pwd")
+				secret.Data[OpenStackUsername] = []byte("user")
+
+				err := validateSecret(secret)
+				Expect(err).To(ConsistOf(
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Type":  BeEquivalentTo("FieldValueForbidden"),
+						"Field": Equal("data[accessTokenFile]"),
+					})),
+				))
+			})
+		})
+
+		Context("with clouds.yaml", func() {
+			BeforeEach(func() {
+				secret = &corev1.Secret{
+					Data: map[string][]byte{
+						OpenStackCloudsYAML: []byte(`clouds:
+  openstack:
+    auth:
+      auth_url: https://keystone.example.com/v3
+      username: user
+      project_name: tenant
+      user_domain_name: domain
+`),
+						OpenStackSecureYAML: []byte(`clouds:
+  openstack:
+    auth:
+      password: pwd
+`),
+						OpenStackCloud: []byte("openstack"),
+					},
+				}
+			})
+
+			It("should not fail", func() {
+				err := validateSecret(secret).ToAggregate()
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("should fail if the cloud is missing", func() {
+				delete(secret.Data, OpenStackCloud)
+
+				err := validateSecret(secret)
+				Expect(err).To(ConsistOf(
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Type":  BeEquivalentTo("FieldValueRequired"),
+						"Field": Equal("data[cloud]"),
+					})),
+				))
+			})
+
+			It("should fail if the cloud is not defined", func() {
+				secret.Data[OpenStackCloud] = []byte("other")
+
+				err := validateSecret(secret)
+				Expect(err).To(ConsistOf(
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Type":  BeEquivalentTo("FieldValueInvalid"),
+						"Field": Equal("data[cloud]"),
+					})),
+				))
+			})
+
+			It("should fail if clouds.yaml is malformed", func() {
+				secret.Data[OpenStackCloudsYAML] = []byte("clouds: [")
+
+				err := validateSecret(secret)
+				Expect(err).To(ConsistOf(
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Type":  BeEquivalentTo("FieldValueInvalid"),
+						"Field": Equal("data[clouds.yaml]"),
+					})),
+				))
+			})
+
+			It("should fail if individual credentials are given in addition", func() {
+				secret.Data[OpenStackAuthURL] = []byte("auth")
+				secret.Data[OpenStackPassword] = []byte("pwd")
+
+				err := validateSecret(secret)
+				Expect(err).To(ConsistOf(
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Type":  BeEquivalentTo("FieldValueForbidden"),
+						"Field": Equal("data[authURL]"),
+					})),
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Type":  BeEquivalentTo("FieldValueForbidden"),
+						"Field": Equal("data[password]"),
+					})),
+				))
+			})
+		})
 	})
 
 	Describe("#UserData", func() {
@@ -0,0 +1,130 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+)
+
+// factoryCacheIdleTimeout is the time after which unused Factories are evicted, e.g. the ones of deleted secrets.
+const factoryCacheIdleTimeout = time.Hour
+
+// FactoryCache caches authenticated Factories, so that subsequent requests using the same credentials can reuse the
+// token and service catalog of an existing provider client instead of authenticating again.
+type FactoryCache struct {
+	// mutex guards the entries only, the Factories are created and refreshed under the lock of their entry, so that a
+	// slow authentication for one secret does not block the requests for other secrets.
+	mutex      sync.Mutex
+	entries    map[string]*factoryCacheEntry
+	rateLimits RateLimits
+	httpOpts   HTTPOptions
+	now        func() time.Time
+}
+
+type factoryCacheEntry struct {
+	// lastUsed is guarded by the mutex of the FactoryCache.
+	lastUsed time.Time
+
+	mutex   sync.Mutex
+	hash    string
+	factory *Factory
+}
+
+// NewFactoryCache returns a new, empty FactoryCache. The requests of the created Factories are limited by the given
+// rate limits and sent by HTTP clients configured with the given options.
+func NewFactoryCache(rateLimits RateLimits, httpOpts HTTPOptions) *FactoryCache {
+	return &FactoryCache{
+		entries:    map[string]*factoryCacheEntry{},
+		rateLimits: rateLimits,
+		httpOpts:   httpOpts,
+		now:        time.Now,
+	}
+}
+
+// GetOrCreate returns the cached Factory for the supplied secret. A new Factory is created if there is no cached Factory
+// yet, or if the credentials stored in the secret have changed since the cached Factory was created.
+func (c *FactoryCache) GetOrCreate(secret *corev1.Secret) (*Factory, error) {
+	if secret == nil {
+		return nil, fmt.Errorf("secret cannot be nil")
+	}
+	if secret.Data == nil {
+		return nil, fmt.Errorf("secret does not contain any data")
+	}
+
+	hash, err := hashCredentials(extractCredentialsFromSecretData(secret.Data))
+	if err != nil {
+		return nil, err
+	}
+
+	// secrets without an identity are cached by their credentials only
+	key := hash
+	if secret.Name != "" {
+		key = secret.Namespace + "/" + secret.Name
+	}
+
+	entry := c.entry(key)
+	entry.mutex.Lock()
+	defer entry.mutex.Unlock()
+
+	if entry.factory != nil {
+		if entry.hash == hash {
+			if err := entry.factory.refreshExpiringToken(); err != nil {
+				klog.Warningf("failed to refresh token of cached OpenStack client for secret %q: %v", key, err)
+			}
+			return entry.factory, nil
+		}
+		klog.V(3).Infof("credentials of secret %q have changed, evicting cached OpenStack client", key)
+		entry.hash, entry.factory = "", nil
+	}
+
+	factory, err := newFactoryFromSecretData(secret.Data, c.rateLimits, c.httpOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	entry.hash, entry.factory = hash, factory
+	return factory, nil
+}
+
+// entry returns the entry for the key, creating it if necessary, and evicts the entries which have not been used within
+// the factoryCacheIdleTimeout.
+func (c *FactoryCache) entry(key string) *factoryCacheEntry {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := c.now()
+	for k, e := range c.entries {
+		if k != key && now.Sub(e.lastUsed) > factoryCacheIdleTimeout {
+			klog.V(3).Infof("evicting cached OpenStack client for secret %q, which has not been used since %s", k, e.lastUsed.Format(time.RFC3339))
+			delete(c.entries, k)
+		}
+	}
+
+	e, ok := c.entries[key]
+	if !ok {
+		e = &factoryCacheEntry{}
+		c.entries[key] = e
+	}
+	e.lastUsed = now
+	return e
+}
+
+// hashCredentials computes a hash over all credential fields.
+func hashCredentials(creds *credentials) (string, error) {
+	raw, err := json.Marshal(creds)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash credentials: %w", err)
+	}
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,102 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("FactoryCache", func() {
+	var (
+		keystone *fakeKeystone
+		cache    *FactoryCache
+		now      time.Time
+	)
+
+	newSecret := func(name, applicationCredentialSecret string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "shoot", Name: name},
+			Data:       keystone.applicationCredentialSecret(applicationCredentialSecret),
+		}
+	}
+
+	BeforeEach(func() {
+		keystone = newFakeKeystone()
+		keystone.accepted.Insert("secret", "rotated")
+		DeferCleanup(keystone.close)
+
+		now = time.Now()
+		cache = NewFactoryCache(RateLimits{}, DefaultHTTPOptions())
+		cache.now = func() time.Time { return now }
+	})
+
+	It("should reuse the Factory of unchanged credentials", func() {
+		factory, err := cache.GetOrCreate(newSecret("a", "secret"))
+		Expect(err).NotTo(HaveOccurred())
+
+		cached, err := cache.GetOrCreate(newSecret("a", "secret"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cached).To(BeIdenticalTo(factory))
+		Expect(keystone.requests()).To(HaveLen(1))
+	})
+
+	It("should replace the Factory if the credentials have changed", func() {
+		factory, err := cache.GetOrCreate(newSecret("a", "secret"))
+		Expect(err).NotTo(HaveOccurred())
+
+		replaced, err := cache.GetOrCreate(newSecret("a", "rotated"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(replaced).NotTo(BeIdenticalTo(factory))
+		Expect(keystone.requests()).To(Equal([]string{"secret", "rotated"}))
+	})
+
+	It("should not cache a failed authentication", func() {
+		_, err := cache.GetOrCreate(newSecret("a", "wrong"))
+		Expect(IsUnauthenticated(err)).To(BeTrue())
+
+		_, err = cache.GetOrCreate(newSecret("a", "secret"))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should evict Factories which have not been used recently", func() {
+		_, err := cache.GetOrCreate(newSecret("deleted", "secret"))
+		Expect(err).NotTo(HaveOccurred())
+
+		now = now.Add(factoryCacheIdleTimeout + time.Minute)
+		_, err = cache.GetOrCreate(newSecret("a", "secret"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cache.entries).To(HaveLen(1))
+		Expect(cache.entries).To(HaveKey("shoot/a"))
+	})
+
+	It("should not block other secrets during a slow authentication", func() {
+		release := make(chan struct{})
+		DeferCleanup(func() { close(release) })
+		keystone.beforeAuth = func(secret string) {
+			if secret == "slow" {
+				<-release
+			}
+		}
+		keystone.accepted.Insert("slow")
+
+		go func() {
+			defer GinkgoRecover()
+			_, _ = cache.GetOrCreate(newSecret("slow", "slow"))
+		}()
+		Eventually(keystone.requests).Should(ContainElement("slow"))
+
+		done := make(chan error)
+		go func() {
+			_, err := cache.GetOrCreate(newSecret("a", "secret"))
+			done <- err
+		}()
+		Eventually(done).Should(Receive(BeNil()))
+	})
+})
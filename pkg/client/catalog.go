@@ -0,0 +1,105 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/identity/v3/tokens"
+	"github.com/gophercloud/gophercloud/openstack/utils"
+	"k8s.io/klog/v2"
+)
+
+const unknownService = "unknown"
+
+// catalogServices maps the service types of the service catalog to the service names used as metric labels.
+var catalogServices = map[string]string{
+	"compute":       novaService,
+	"network":       neutronService,
+	"block-storage": cinderService,
+	"volumev3":      cinderService,
+	"image":         glanceService,
+	"identity":      "keystone",
+}
+
+// serviceCatalog resolves the OpenStack service a request is sent to, so that the transports wrapping the provider
+// client can attribute requests to services.
+type serviceCatalog struct {
+	mutex sync.RWMutex
+	// services maps the base URLs of the service endpoints to the service names
+	services map[string]string
+	// overrides maps the base URLs of the endpoint overrides to the service names
+	overrides map[string]string
+}
+
+// newServiceCatalog returns a service catalog, which resolves the given endpoint overrides keyed by service type in
+// addition to the endpoints of the token's service catalog.
+func newServiceCatalog(endpointOverrides map[string]string) *serviceCatalog {
+	overrides := map[string]string{}
+	for serviceType, url := range endpointOverrides {
+		if base, err := utils.BaseEndpoint(url); err == nil {
+			overrides[base] = serviceName(serviceType)
+		}
+	}
+	return &serviceCatalog{
+		services:  overrides,
+		overrides: overrides,
+	}
+}
+
+// update registers the endpoints of the service catalog of the provider client's token.
+func (c *serviceCatalog) update(providerClient *gophercloud.ProviderClient) {
+	result, ok := providerClient.GetAuthResult().(tokens.CreateResult)
+	if !ok {
+		return
+	}
+	catalog, err := result.ExtractServiceCatalog()
+	if err != nil {
+		klog.V(3).Infof("failed to extract service catalog: %v", err)
+		return
+	}
+
+	services := map[string]string{}
+	for _, entry := range catalog.Entries {
+		for _, endpoint := range entry.Endpoints {
+			if base, err := utils.BaseEndpoint(endpoint.URL); err == nil {
+				services[base] = serviceName(entry.Type)
+			}
+		}
+	}
+	for base, service := range c.overrides {
+		services[base] = service
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.services = services
+}
+
+// serviceName returns the name of the service of the service type.
+func serviceName(serviceType string) string {
+	if service, ok := catalogServices[serviceType]; ok {
+		return service
+	}
+	return serviceType
+}
+
+// serviceOf returns the name of the service the request is sent to, based on the longest matching endpoint.
+func (c *serviceCatalog) serviceOf(req *http.Request) string {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	url := req.URL.String()
+	service, matched := unknownService, ""
+	for base, name := range c.services {
+		if strings.HasPrefix(url, base) && len(base) > len(matched) {
+			service, matched = name, base
+		}
+	}
+	return service
+}
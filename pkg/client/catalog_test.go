@@ -0,0 +1,63 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("serviceCatalog", func() {
+	var keystone *fakeKeystone
+
+	BeforeEach(func() {
+		keystone = newFakeKeystone()
+		keystone.accepted.Insert("secret")
+		DeferCleanup(keystone.close)
+	})
+
+	serviceOf := func(catalog *serviceCatalog, url string) string {
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		Expect(err).NotTo(HaveOccurred())
+		return catalog.serviceOf(req)
+	}
+
+	It("should resolve the services from the service catalog of the token", func() {
+		catalog := newServiceCatalog(nil)
+		Expect(serviceOf(catalog, keystone.server.URL+"/compute/v2.1/servers")).To(Equal(unknownService))
+
+		catalog.update(keystone.providerClient("secret"))
+		Expect(serviceOf(catalog, keystone.server.URL+"/compute/v2.1/servers")).To(Equal(novaService))
+		Expect(serviceOf(catalog, keystone.server.URL+"/network/v2.0/ports")).To(Equal(neutronService))
+		Expect(serviceOf(catalog, keystone.server.URL+"/volume/v3/project-id/volumes")).To(Equal(cinderService))
+		Expect(serviceOf(catalog, keystone.server.URL+"/image/v2/images")).To(Equal(glanceService))
+		Expect(serviceOf(catalog, "https://other.example.org/v2.1/servers")).To(Equal(unknownService))
+	})
+
+	It("should resolve the services of the endpoint overrides", func() {
+		catalog := newServiceCatalog(map[string]string{
+			"compute":   "https://nova.example.org/v2.1",
+			"placement": "https://placement.example.org",
+		})
+		Expect(serviceOf(catalog, "https://nova.example.org/v2.1/servers")).To(Equal(novaService))
+		Expect(serviceOf(catalog, "https://placement.example.org/resource_providers")).To(Equal("placement"))
+
+		// the overrides are kept once the service catalog of the token is known
+		catalog.update(keystone.providerClient("secret"))
+		Expect(serviceOf(catalog, "https://nova.example.org/v2.1/servers")).To(Equal(novaService))
+		Expect(serviceOf(catalog, keystone.server.URL+"/network/v2.0/ports")).To(Equal(neutronService))
+	})
+
+	It("should prefer the longest matching endpoint", func() {
+		catalog := newServiceCatalog(map[string]string{
+			"compute":  "https://openstack.example.org/compute/v2.1",
+			"identity": "https://openstack.example.org",
+		})
+		Expect(serviceOf(catalog, "https://openstack.example.org/compute/v2.1/servers")).To(Equal(novaService))
+		Expect(serviceOf(catalog, "https://openstack.example.org/v3/auth/tokens")).To(Equal("keystone"))
+	})
+})
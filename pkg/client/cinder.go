@@ -5,12 +5,16 @@
 package client
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	"github.com/gophercloud/gophercloud"
 	"github.com/gophercloud/gophercloud/openstack"
+	"github.com/gophercloud/gophercloud/openstack/blockstorage/apiversions"
 	"github.com/gophercloud/gophercloud/openstack/blockstorage/v3/volumes"
 	utilGroups "github.com/gophercloud/utils/openstack/blockstorage/v3/volumes"
+	"k8s.io/klog/v2"
 )
 
 const (
@@ -27,72 +31,141 @@ const (
 	VolumeStatusError = "error"
 	// VolumeStatusInUse indicates that the volume is currently in use.
 	VolumeStatusInUse = "in-use"
+	// VolumeStatusDetaching indicates that the volume is being detached.
+	VolumeStatusDetaching = "detaching"
 )
 
 var _ Storage = &cinderV3{}
 
 type cinderV3 struct {
 	serviceClient *gophercloud.ServiceClient
+	// features maps the supported optional features to the microversion they require
+	features map[Feature]string
 }
 
-func newCinderV3(providerClient *gophercloud.ProviderClient, eo gophercloud.EndpointOpts) (*cinderV3, error) {
-	storage, err := openstack.NewBlockStorageV3(providerClient, eo)
+func newCinderV3(providerClient *gophercloud.ProviderClient, eo gophercloud.EndpointOpts, endpoint string, microversions *microversionCache) (*cinderV3, error) {
+	storage, err := newServiceClient(providerClient, eo, endpoint, openstack.NewBlockStorageV3)
 	if err != nil {
 		return nil, fmt.Errorf("could not initialize storage client: %v", err)
 	}
 
-	return &cinderV3{
+	c := &cinderV3{
 		serviceClient: storage,
-	}, nil
+	}
+
+	maxMicroversion, err := microversions.get(storage.Endpoint, c.maxMicroversion)
+	if err != nil {
+		// older clouds may not expose the version document, fall back to the base API behavior
+		klog.Warningf("failed to discover block storage API microversion of endpoint %q: %v", storage.Endpoint, err)
+	}
+	c.features = featureMicroversions(storageFeatureMicroversions, maxMicroversion)
+	return c, nil
 }
 
-// CreateVolume creates a Cinder volume.
-func (c *cinderV3) CreateVolume(opts volumes.CreateOptsBuilder) (*volumes.Volume, error) {
-	v, err := volumes.Create(c.serviceClient, opts).Extract()
-	onCall(cinderService)
+// maxMicroversion returns the maximum microversion supported by the block storage API.
+func (c *cinderV3) maxMicroversion() (string, error) {
+	start := time.Now()
+	pages, err := apiversions.List(c.serviceClient).AllPages()
+	onCall(cinderService, "ListAPIVersions", start, err)
 	if err != nil {
 		onFailure(cinderService)
+		return "", withRequestID(err)
+	}
+
+	version, err := apiversions.ExtractAPIVersion(pages, "v3.0")
+	if err != nil {
+		return "", err
+	}
+	return version.Version, nil
+}
+
+// Supports reports whether the block storage API supports the optional feature.
+func (c *cinderV3) Supports(feature Feature) bool {
+	_, ok := c.features[feature]
+	return ok
+}
+
+// CreateVolume creates a Cinder volume.
+func (c *cinderV3) CreateVolume(ctx context.Context, opts volumes.CreateOptsBuilder) (*volumes.Volume, error) {
+	serviceClient := c.serviceClient
+	if multiattach, err := isMultiattachVolume(opts); err != nil {
 		return nil, err
+	} else if multiattach {
+		microversion, ok := c.features[FeatureMultiattach]
+		if !ok {
+			return nil, fmt.Errorf("multiattach volumes require feature %s, which is not supported by the block storage API", FeatureMultiattach)
+		}
+		serviceClient = withMicroversion(c.serviceClient, microversion)
+	}
+
+	start := time.Now()
+	v, err := volumes.Create(withContext(ctx, serviceClient), opts).Extract()
+	onCall(cinderService, "CreateVolume", start, err)
+	if err != nil {
+		onFailure(cinderService)
+		return nil, withRequestID(err)
 	}
 	return v, nil
 }
 
 // GetVolume retrieves information about a volume.
-func (c *cinderV3) GetVolume(id string) (*volumes.Volume, error) {
-	return volumes.Get(c.serviceClient, id).Extract()
+func (c *cinderV3) GetVolume(ctx context.Context, id string) (*volumes.Volume, error) {
+	start := time.Now()
+	v, err := volumes.Get(withContext(ctx, c.serviceClient), id).Extract()
+	onCall(cinderService, "GetVolume", start, err)
+	if err != nil {
+		if !IsNotFoundError(err) {
+			onFailure(cinderService)
+		}
+		return nil, withRequestID(err)
+	}
+	return v, nil
 }
 
 // DeleteVolume deletes a volume
-func (c *cinderV3) DeleteVolume(id string) error {
-	err := volumes.Delete(c.serviceClient, id, volumes.DeleteOpts{}).ExtractErr()
-	onCall(cinderService)
+func (c *cinderV3) DeleteVolume(ctx context.Context, id string) error {
+	start := time.Now()
+	err := volumes.Delete(withContext(ctx, c.serviceClient), id, volumes.DeleteOpts{}).ExtractErr()
+	onCall(cinderService, "DeleteVolume", start, err)
 	if err != nil {
 		onFailure(cinderService)
-		return err
+		return withRequestID(err)
 	}
 	return nil
 }
 
 // VolumeIDFromName resolves the given volume name to a unique ID.
-func (c *cinderV3) VolumeIDFromName(name string) (string, error) {
-	id, err := utilGroups.IDFromName(c.serviceClient, name)
+func (c *cinderV3) VolumeIDFromName(ctx context.Context, name string) (string, error) {
+	start := time.Now()
+	id, err := utilGroups.IDFromName(withContext(ctx, c.serviceClient), name)
 
-	onCall(cinderService)
+	onCall(cinderService, "VolumeIDFromName", start, err)
 	if err != nil {
 		onFailure(cinderService)
-		return "", err
+		return "", withRequestID(err)
 	}
 	return id, nil
 }
 
 // ListVolumes lists all volumes
-func (c *cinderV3) ListVolumes(opts volumes.ListOptsBuilder) ([]volumes.Volume, error) {
-	vols, err := volumes.List(c.serviceClient, opts).AllPages()
-	onCall(cinderService)
+func (c *cinderV3) ListVolumes(ctx context.Context, opts volumes.ListOptsBuilder) ([]volumes.Volume, error) {
+	start := time.Now()
+	vols, err := volumes.List(withContext(ctx, c.serviceClient), opts).AllPages()
+	onCall(cinderService, "ListVolumes", start, err)
 	if err != nil {
 		onFailure(cinderService)
-		return nil, err
+		return nil, withRequestID(err)
 	}
 
 	return volumes.ExtractVolumes(vols)
 }
+
+func isMultiattachVolume(opts volumes.CreateOptsBuilder) (bool, error) {
+	body, err := opts.ToVolumeCreateMap()
+	if err != nil {
+		return false, err
+	}
+	volume, _ := body["volume"].(map[string]interface{})
+	multiattach, _ := volume["multiattach"].(bool)
+	return multiattach, nil
+}
@@ -0,0 +1,37 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"context"
+
+	"github.com/gophercloud/gophercloud"
+)
+
+// withContext returns a copy of the service client whose requests are bound to the context. The provider client is
+// copied as well, because gophercloud only supports a single context per provider client. The copy shares the token of
+// the original provider client and reauthenticates through it, so that a refreshed token is shared by all copies.
+func withContext(ctx context.Context, serviceClient *gophercloud.ServiceClient) *gophercloud.ServiceClient {
+	provider := serviceClient.ProviderClient
+
+	pc := *provider
+	// the copy needs its own locks, otherwise reauthenticating through the original provider client deadlocks
+	pc.UseTokenLock()
+	pc.CopyTokenFrom(provider)
+	pc.Context = ctx
+	if provider.ReauthFunc != nil {
+		pc.ReauthFunc = func() error {
+			if err := provider.Reauthenticate(pc.Token()); err != nil {
+				return err
+			}
+			pc.CopyTokenFrom(provider)
+			return nil
+		}
+	}
+
+	sc := *serviceClient
+	sc.ProviderClient = &pc
+	return &sc
+}
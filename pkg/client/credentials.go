@@ -5,11 +5,23 @@
 package client
 
 import (
+	"fmt"
 	"strings"
 
+	"github.com/gophercloud/utils/openstack/clientconfig"
+	"sigs.k8s.io/yaml"
+
 	"github.com/gardener/machine-controller-manager-provider-openstack/pkg/apis/cloudprovider"
 )
 
+// endpointOverrideKeys maps the secret keys overriding service endpoints to the service types.
+var endpointOverrideKeys = map[string]string{
+	cloudprovider.OpenStackComputeEndpoint: computeServiceType,
+	cloudprovider.OpenStackNetworkEndpoint: networkServiceType,
+	cloudprovider.OpenStackVolumeEndpoint:  volumeServiceType,
+	cloudprovider.OpenStackImageEndpoint:   imageServiceType,
+}
+
 type credentials struct {
 	DomainName     string
 	DomainID       string
@@ -26,12 +38,40 @@ type credentials struct {
 	ApplicationCredentialName   string
 	ApplicationCredentialSecret string
 
+	// NextApplicationCredentialID, NextApplicationCredentialName and NextApplicationCredentialSecret specify the
+	// application credential, which replaces the credentials above during a rotation.
+	NextApplicationCredentialID     string
+	NextApplicationCredentialName   string
+	NextApplicationCredentialSecret string
+
 	CACert     []byte
 	ClientKey  []byte
 	ClientCert []byte
 	Insecure   bool
+	// CACertAppend is set if the CACert is trusted in addition to the system certificate authorities.
+	CACertAppend bool
+	// ProxyURL is the proxy used instead of the one of the environment.
+	ProxyURL string
 
 	AuthURL string
+
+	// CloudsYAML and SecureYAML are the contents of the clouds.yaml and secure.yaml files. If CloudsYAML is set, the
+	// credentials of the selected Cloud are used instead of the individual fields above, except for the TLS settings.
+	CloudsYAML []byte
+	SecureYAML []byte
+	Cloud      string
+
+	// IdentityProvider is set if an OIDC access token is exchanged for a token scoped to the tenant instead of
+	// authenticating with static credentials.
+	IdentityProvider string
+	Protocol         string
+	AccessToken      string
+	AccessTokenFile  string
+
+	// EndpointInterface selects the interface of the endpoints in the service catalog.
+	EndpointInterface string
+	// EndpointOverrides maps service types to the URLs used instead of the endpoints of the service catalog.
+	EndpointOverrides map[string]string
 }
 
 func extractCredentialsFromSecretData(data map[string][]byte) *credentials {
@@ -47,6 +87,10 @@ func extractCredentialsFromSecretData(data map[string][]byte) *credentials {
 	applicationCredentialName := data[cloudprovider.OpenStackApplicationCredentialName]
 	applicationCredentialSecret := data[cloudprovider.OpenStackApplicationCredentialSecret]
 
+	nextApplicationCredentialID := data[cloudprovider.OpenStackNextApplicationCredentialID]
+	nextApplicationCredentialName := data[cloudprovider.OpenStackNextApplicationCredentialName]
+	nextApplicationCredentialSecret := data[cloudprovider.OpenStackNextApplicationCredentialSecret]
+
 	// optional OS_USER_DOMAIN_NAME
 	userDomainName := data[cloudprovider.OpenStackUserDomainName]
 	// optional OS_USER_DOMAIN_ID
@@ -71,6 +115,28 @@ func extractCredentialsFromSecretData(data map[string][]byte) *credentials {
 	}
 
 	insecure := strings.TrimSpace(string(data[cloudprovider.OpenStackInsecure])) == "true"
+	caCertAppend := strings.TrimSpace(string(data[cloudprovider.OpenStackCACertAppend])) == "true"
+	proxyURL := data[cloudprovider.OpenStackProxyURL]
+
+	cloudsYAML := data[cloudprovider.OpenStackCloudsYAML]
+	secureYAML := data[cloudprovider.OpenStackSecureYAML]
+	cloud := data[cloudprovider.OpenStackCloud]
+
+	identityProvider := data[cloudprovider.OpenStackIdentityProvider]
+	protocol := data[cloudprovider.OpenStackProtocol]
+	accessToken := data[cloudprovider.OpenStackAccessToken]
+	accessTokenFile := data[cloudprovider.OpenStackAccessTokenFile]
+
+	endpointInterface := data[cloudprovider.OpenStackEndpointInterface]
+	var endpointOverrides map[string]string
+	for key, serviceType := range endpointOverrideKeys {
+		if url := strings.TrimSpace(string(data[key])); url != "" {
+			if endpointOverrides == nil {
+				endpointOverrides = map[string]string{}
+			}
+			endpointOverrides[serviceType] = url
+		}
+	}
 
 	return &credentials{
 		DomainName:                  strings.TrimSpace(string(domainName)),
@@ -89,5 +155,69 @@ func extractCredentialsFromSecretData(data map[string][]byte) *credentials {
 		ClientKey:                   clientKey,
 		CACert:                      caCert,
 		Insecure:                    insecure,
+		CACertAppend:                caCertAppend,
+		ProxyURL:                    strings.TrimSpace(string(proxyURL)),
+		CloudsYAML:                  cloudsYAML,
+		SecureYAML:                  secureYAML,
+		Cloud:                       strings.TrimSpace(string(cloud)),
+		IdentityProvider:            strings.TrimSpace(string(identityProvider)),
+		Protocol:                    strings.TrimSpace(string(protocol)),
+		AccessToken:                 strings.TrimSpace(string(accessToken)),
+		AccessTokenFile:             strings.TrimSpace(string(accessTokenFile)),
+		EndpointInterface:           strings.TrimSpace(string(endpointInterface)),
+		EndpointOverrides:           endpointOverrides,
+
+		NextApplicationCredentialID:     strings.TrimSpace(string(nextApplicationCredentialID)),
+		NextApplicationCredentialName:   strings.TrimSpace(string(nextApplicationCredentialName)),
+		NextApplicationCredentialSecret: strings.TrimSpace(string(nextApplicationCredentialSecret)),
+	}
+}
+
+// next returns the credentials, which replace these credentials during a rotation, or nil if there are none.
+func (c *credentials) next() *credentials {
+	if c.NextApplicationCredentialSecret == "" {
+		return nil
+	}
+
+	next := *c
+	next.Password = ""
+	next.ApplicationCredentialID = c.NextApplicationCredentialID
+	next.ApplicationCredentialName = c.NextApplicationCredentialName
+	next.ApplicationCredentialSecret = c.NextApplicationCredentialSecret
+	return &next
+}
+
+// cloudsYAMLOpts loads the clouds.yaml and secure.yaml files from the credentials instead of the file system.
+type cloudsYAMLOpts struct {
+	cloudsYAML []byte
+	secureYAML []byte
+}
+
+var _ clientconfig.YAMLOptsBuilder = cloudsYAMLOpts{}
+
+// LoadCloudsYAML implements the clientconfig.YAMLOptsBuilder interface.
+func (o cloudsYAMLOpts) LoadCloudsYAML() (map[string]clientconfig.Cloud, error) {
+	return unmarshalClouds(o.cloudsYAML)
+}
+
+// LoadSecureCloudsYAML implements the clientconfig.YAMLOptsBuilder interface.
+func (o cloudsYAMLOpts) LoadSecureCloudsYAML() (map[string]clientconfig.Cloud, error) {
+	if len(o.secureYAML) == 0 {
+		return nil, nil
+	}
+	return unmarshalClouds(o.secureYAML)
+}
+
+// LoadPublicCloudsYAML implements the clientconfig.YAMLOptsBuilder interface. Profiles of public clouds are not
+// supported, because they would have to be read from the file system.
+func (o cloudsYAMLOpts) LoadPublicCloudsYAML() (map[string]clientconfig.Cloud, error) {
+	return nil, nil
+}
+
+func unmarshalClouds(data []byte) (map[string]clientconfig.Cloud, error) {
+	var clouds clientconfig.Clouds
+	if err := yaml.Unmarshal(data, &clouds); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal yaml: %w", err)
 	}
+	return clouds.Clouds, nil
 }
@@ -6,10 +6,68 @@ package client
 
 import (
 	"errors"
+	"fmt"
+	"net/http"
 
 	"github.com/gophercloud/gophercloud"
 )
 
+// requestIDHeaders are the response headers carrying the ID OpenStack services assign to a request, in order of
+// preference.
+var requestIDHeaders = []string{"X-Openstack-Request-Id", "X-Compute-Request-Id"}
+
+// RequestError is an error returned by an OpenStack service, which carries the ID the service assigned to the failed
+// request. The ID allows the operators of the cloud to find the request in the logs of the service.
+type RequestError struct {
+	// RequestID is the ID of the failed request.
+	RequestID string
+	// Err is the error returned by the service.
+	Err error
+}
+
+func (e *RequestError) Error() string {
+	return fmt.Sprintf("%v [request ID: %s]", e.Err, e.RequestID)
+}
+
+func (e *RequestError) Unwrap() error {
+	return e.Err
+}
+
+// RequestID returns the ID of the failed request the error was caused by, or an empty string if it is unknown.
+func RequestID(err error) string {
+	var e *RequestError
+	if errors.As(err, &e) {
+		return e.RequestID
+	}
+	return ""
+}
+
+// withRequestID wraps the error into a RequestError if the response of the failed request carries a request ID.
+func withRequestID(err error) error {
+	if err == nil || RequestID(err) != "" {
+		return err
+	}
+
+	var respErr gophercloud.ErrUnexpectedResponseCode
+	if !errors.As(err, &respErr) {
+		return err
+	}
+	if id := requestIDFromHeader(respErr.ResponseHeader); id != "" {
+		return &RequestError{RequestID: id, Err: err}
+	}
+	return err
+}
+
+// requestIDFromHeader returns the request ID from the response header, or an empty string if there is none.
+func requestIDFromHeader(header http.Header) string {
+	for _, name := range requestIDHeaders {
+		if id := header.Get(name); id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
 // IsNotFoundError checks if an error returned by OpenStack service calls is caused by HTTP 404 status code.
 func IsNotFoundError(err error) bool {
 	if err == nil {
@@ -0,0 +1,89 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gophercloud/gophercloud"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Errors", func() {
+	unexpectedResponseCode := func(status int, header http.Header) gophercloud.ErrUnexpectedResponseCode {
+		return gophercloud.ErrUnexpectedResponseCode{
+			Method:         http.MethodGet,
+			URL:            "https://nova.example.org/v2.1/servers",
+			Actual:         status,
+			ResponseHeader: header,
+		}
+	}
+
+	Describe("#withRequestID", func() {
+		It("should wrap errors of responses with a request ID", func() {
+			cause := gophercloud.ErrDefault500{ErrUnexpectedResponseCode: unexpectedResponseCode(500, http.Header{"X-Openstack-Request-Id": {"req-1"}})}
+
+			err := withRequestID(cause)
+			Expect(RequestID(err)).To(Equal("req-1"))
+			Expect(err).To(MatchError(ContainSubstring("[request ID: req-1]")))
+			Expect(errors.As(err, &gophercloud.ErrDefault500{})).To(BeTrue())
+		})
+
+		It("should fall back to the compute request ID", func() {
+			err := withRequestID(gophercloud.ErrDefault404{ErrUnexpectedResponseCode: unexpectedResponseCode(404, http.Header{"X-Compute-Request-Id": {"req-2"}})})
+			Expect(RequestID(err)).To(Equal("req-2"))
+			Expect(IsNotFoundError(err)).To(BeTrue())
+		})
+
+		It("should not wrap errors without a request ID", func() {
+			cause := gophercloud.ErrDefault500{ErrUnexpectedResponseCode: unexpectedResponseCode(500, http.Header{})}
+			Expect(withRequestID(cause)).To(Equal(cause))
+
+			connectionErr := errors.New("connection refused")
+			Expect(withRequestID(connectionErr)).To(Equal(connectionErr))
+			Expect(withRequestID(nil)).To(BeNil())
+		})
+
+		It("should not wrap errors twice", func() {
+			err := withRequestID(gophercloud.ErrDefault500{ErrUnexpectedResponseCode: unexpectedResponseCode(500, http.Header{"X-Openstack-Request-Id": {"req-1"}})})
+			Expect(withRequestID(err)).To(BeIdenticalTo(err))
+		})
+	})
+
+	DescribeTable("#IsNotFoundError",
+		func(err error, expected bool) {
+			Expect(IsNotFoundError(err)).To(Equal(expected))
+		},
+		Entry("nil", nil, false),
+		Entry("404", gophercloud.ErrDefault404{}, true),
+		Entry("wrapped 404", fmt.Errorf("failed: %w", gophercloud.ErrDefault404{}), true),
+		Entry("404 with request ID", &RequestError{RequestID: "req-1", Err: gophercloud.ErrDefault404{}}, true),
+		Entry("unresolved name", gophercloud.ErrResourceNotFound{}, true),
+		Entry("500", gophercloud.ErrDefault500{}, false),
+	)
+
+	DescribeTable("#IsUnauthenticated",
+		func(err error, expected bool) {
+			Expect(IsUnauthenticated(err)).To(Equal(expected))
+		},
+		Entry("nil", nil, false),
+		Entry("401", gophercloud.ErrDefault401{}, true),
+		Entry("401 with request ID", &RequestError{RequestID: "req-1", Err: gophercloud.ErrDefault401{}}, true),
+		Entry("403", gophercloud.ErrDefault403{}, false),
+	)
+
+	DescribeTable("#IsUnauthorized",
+		func(err error, expected bool) {
+			Expect(IsUnauthorized(err)).To(Equal(expected))
+		},
+		Entry("nil", nil, false),
+		Entry("403", gophercloud.ErrDefault403{}, true),
+		Entry("wrapped 403", fmt.Errorf("failed: %w", gophercloud.ErrDefault403{}), true),
+		Entry("401", gophercloud.ErrDefault401{}, false),
+	)
+})
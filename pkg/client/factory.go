@@ -6,23 +6,36 @@ package client
 
 import (
 	"crypto/tls"
-	"crypto/x509"
 	"fmt"
 	"net/http"
-	"runtime"
-	"strings"
+	"time"
 
 	"github.com/gophercloud/gophercloud"
 	"github.com/gophercloud/gophercloud/openstack"
-	"github.com/gophercloud/utils/client"
+	"github.com/gophercloud/gophercloud/openstack/identity/v3/tokens"
 	"github.com/gophercloud/utils/openstack/clientconfig"
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/klog/v2"
+)
+
+// tokenRefreshWindow is the remaining lifetime below which the token of a cached provider client is refreshed.
+const tokenRefreshWindow = 5 * time.Minute
+
+const (
+	// The service types of the service catalog the clients are created for.
+	computeServiceType = "compute"
+	networkServiceType = "network"
+	volumeServiceType  = "volumev3"
+	imageServiceType   = "image"
 )
 
 // Factory can create clients for Nova and Neutron OpenStack services.
 type Factory struct {
 	providerClient *gophercloud.ProviderClient
+	microversions  *microversionCache
+	// options are applied to all clients before the options passed when creating a client.
+	options []Option
+	// endpointOverrides maps service types to the URLs used instead of the endpoints of the service catalog.
+	endpointOverrides map[string]string
 }
 
 // Option can modify client parameters by manipulating EndpointOpts.
@@ -30,21 +43,54 @@ type Option func(opts gophercloud.EndpointOpts) gophercloud.EndpointOpts
 
 // NewFactoryFromSecretData can create a Factory from the a kubernetes secret's data.
 func NewFactoryFromSecretData(data map[string][]byte) (*Factory, error) {
+	return newFactoryFromSecretData(data, RateLimits{}, DefaultHTTPOptions())
+}
+
+// newFactoryFromSecretData creates a Factory whose requests are limited by the rate limiter shared by all Factories of
+// the same project, and sent by a HTTP client configured with the given options.
+func newFactoryFromSecretData(data map[string][]byte, limits RateLimits, httpOpts HTTPOptions) (*Factory, error) {
 	if data == nil {
 		return nil, fmt.Errorf("secret does not contain any data")
 	}
 
 	creds := extractCredentialsFromSecretData(data)
-	provider, err := newAuthenticatedProviderClientFromCredentials(creds)
+	provider, err := newAuthenticatedProviderClientFromCredentials(creds, limits, httpOpts)
 	if err != nil {
 		return nil, fmt.Errorf("error creating OpenStack client from credentials: %w", err)
 	}
 
 	return &Factory{
-		providerClient: provider,
+		providerClient:    provider,
+		microversions:     newMicroversionCache(),
+		options:           endpointOptions(creds),
+		endpointOverrides: creds.EndpointOverrides,
 	}, nil
 }
 
+// endpointOptions returns the options for the endpoint interface configured in the credentials.
+func endpointOptions(creds *credentials) []Option {
+	var opts []Option
+	if creds.EndpointInterface != "" {
+		opts = append(opts, WithInterface(creds.EndpointInterface))
+	}
+	return opts
+}
+
+// refreshExpiringToken reauthenticates the provider client if its token expires within the tokenRefreshWindow, so that
+// requests do not fail with an expired token. This matters for tokens obtained with short-lived access tokens, which
+// might have expired by the time the reauthentication is triggered by a rejected request.
+func (f *Factory) refreshExpiringToken() error {
+	result, ok := f.providerClient.GetAuthResult().(tokens.CreateResult)
+	if !ok {
+		return nil
+	}
+	token, err := result.ExtractToken()
+	if err != nil || time.Until(token.ExpiresAt) > tokenRefreshWindow {
+		return nil
+	}
+	return f.providerClient.Reauthenticate(f.providerClient.Token())
+}
+
 // NewFactoryFromSecret can create a Factory from the a kubernetes secret.
 func NewFactoryFromSecret(secret *corev1.Secret) (*Factory, error) {
 	if secret == nil {
@@ -54,13 +100,11 @@ func NewFactoryFromSecret(secret *corev1.Secret) (*Factory, error) {
 	return NewFactoryFromSecretData(secret.Data)
 }
 
-func newAuthenticatedProviderClientFromCredentials(credentials *credentials) (*gophercloud.ProviderClient, error) {
+func newAuthenticatedProviderClientFromCredentials(credentials *credentials, limits RateLimits, httpOpts HTTPOptions) (*gophercloud.ProviderClient, error) {
 	config := &tls.Config{} // #nosec: G402 -- Can be parameterized.
 
 	if credentials.CACert != nil {
-		caCertPool := x509.NewCertPool()
-		caCertPool.AppendCertsFromPEM(credentials.CACert)
-		config.RootCAs = caCertPool
+		config.RootCAs = newRootCAs(credentials.CACert, credentials.CACertAppend)
 	}
 
 	if credentials.Insecure {
@@ -76,33 +120,43 @@ func newAuthenticatedProviderClientFromCredentials(credentials *credentials) (*g
 		config.BuildNameToCertificate()
 	}
 
-	clientOpts := new(clientconfig.ClientOpts)
-	authInfo := &clientconfig.AuthInfo{
-		AuthURL:                     credentials.AuthURL,
-		Username:                    credentials.Username,
-		Password:                    credentials.Password,
-		DomainName:                  credentials.DomainName,
-		DomainID:                    credentials.DomainID,
-		ProjectName:                 credentials.TenantName,
-		ProjectID:                   credentials.TenantID,
-		UserDomainName:              credentials.UserDomainName,
-		UserDomainID:                credentials.UserDomainID,
-		ApplicationCredentialID:     credentials.ApplicationCredentialID,
-		ApplicationCredentialName:   credentials.ApplicationCredentialName,
-		ApplicationCredentialSecret: credentials.ApplicationCredentialSecret,
-	}
-	clientOpts.AuthInfo = authInfo
+	var (
+		identityEndpoint string
+		authenticate     func(*gophercloud.ProviderClient) error
+	)
+	if credentials.IdentityProvider != "" {
+		identityEndpoint = credentials.AuthURL
+		authenticate = newOIDCAuthenticator(credentials).authenticate
+	} else {
+		clientOpts := newClientOpts(credentials)
+		if credentials.CloudsYAML != nil {
+			cloud, err := clientconfig.GetCloudFromYAML(clientOpts)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load cloud %q from clouds.yaml: %w", credentials.Cloud, err)
+			}
+			if cloud.Verify != nil && !*cloud.Verify {
+				config.InsecureSkipVerify = true
+			}
+		}
 
-	if clientOpts.AuthInfo.ApplicationCredentialSecret != "" {
-		clientOpts.AuthType = clientconfig.AuthV3ApplicationCredential
-	}
+		ao, err := clientconfig.AuthOptions(clientOpts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create client auth options: %w", err)
+		}
+		authOptions := []namedAuthOptions{{name: primaryCredential, authOptions: *ao}}
+		if next := credentials.next(); next != nil {
+			nextAO, err := clientconfig.AuthOptions(newClientOpts(next))
+			if err != nil {
+				return nil, fmt.Errorf("failed to create client auth options for %s credentials: %w", nextCredential, err)
+			}
+			authOptions = append(authOptions, namedAuthOptions{name: nextCredential, authOptions: *nextAO})
+		}
 
-	ao, err := clientconfig.AuthOptions(clientOpts)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create client auth options: %w", err)
+		identityEndpoint = ao.IdentityEndpoint
+		authenticate = newRotatingAuthenticator(authOptions...).authenticate
 	}
 
-	provider, err := openstack.NewClient(ao.IdentityEndpoint)
+	provider, err := openstack.NewClient(identityEndpoint)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create authenticated client: %w", err)
 	}
@@ -110,53 +164,68 @@ func newAuthenticatedProviderClientFromCredentials(credentials *credentials) (*g
 	// Set UserAgent
 	provider.UserAgent.Prepend("Machine Controller Provider Openstack")
 
-	transport := &http.Transport{Proxy: http.ProxyFromEnvironment, TLSClientConfig: config}
+	transport, err := newHTTPTransport(credentials, config, httpOpts)
+	if err != nil {
+		return nil, err
+	}
 	provider.HTTPClient = http.Client{
 		Transport: transport,
+		Timeout:   httpOpts.RequestTimeout,
 	}
 
-	if klog.V(6).Enabled() {
-		provider.HTTPClient.Transport = &client.RoundTripper{
-			Rt:     provider.HTTPClient.Transport,
-			Logger: &logger{},
-		}
-	}
+	// requests and responses are logged with credentials and user data redacted
+	provider.HTTPClient.Transport = newLoggingTransport(provider.HTTPClient.Transport)
 
-	err = openstack.Authenticate(provider, *ao)
-	if err != nil {
+	// requests are rate limited, traced and retried around the logging round tripper, so that every attempt is logged
+	// and gets its own span
+	catalog := newServiceCatalog(credentials.EndpointOverrides)
+	rateLimits := newRateLimitTransport(provider.HTTPClient.Transport, catalog)
+	provider.HTTPClient.Transport = newRetryTransport(newTracingTransport(rateLimits, catalog), catalog)
+
+	if err := authenticate(provider); err != nil {
 		return nil, err
 	}
+	catalog.update(provider)
+	if limits.enabled() {
+		rateLimits.limiter.Store(projectRateLimiters.get(identityEndpoint, provider, limits))
+	}
 
 	return provider, nil
 }
 
-type logger struct{}
-
-func (l logger) Printf(format string, args ...interface{}) {
-	// extra check in case, when verbosity has been changed dynamically
-	if klog.V(6).Enabled() {
-		var skip int
-		var found bool
-		gc := "/github.com/gophercloud/gophercloud"
-
-		// detect the depth of the actual function, which calls gophercloud code
-		// 10 is the common depth from the logger to "github.com/gophercloud/gophercloud"
-		for i := 10; i <= 20; i++ {
-			if _, file, _, ok := runtime.Caller(i); ok && !found && strings.Contains(file, gc) {
-				found = true
-				continue
-			} else if ok && found && !strings.Contains(file, gc) {
-				skip = i
-				break
-			} else if !ok {
-				break
-			}
+// newClientOpts returns the options to authenticate with the credentials. If the credentials contain a clouds.yaml file,
+// the selected cloud is loaded from it, otherwise the individual credential fields are used.
+func newClientOpts(credentials *credentials) *clientconfig.ClientOpts {
+	if credentials.CloudsYAML != nil {
+		return &clientconfig.ClientOpts{
+			Cloud: credentials.Cloud,
+			YAMLOpts: cloudsYAMLOpts{
+				cloudsYAML: credentials.CloudsYAML,
+				secureYAML: credentials.SecureYAML,
+			},
 		}
+	}
 
-		for _, v := range strings.Split(fmt.Sprintf(format, args...), "\n") {
-			klog.InfoDepth(skip, v)
-		}
+	clientOpts := &clientconfig.ClientOpts{
+		AuthInfo: &clientconfig.AuthInfo{
+			AuthURL:                     credentials.AuthURL,
+			Username:                    credentials.Username,
+			Password:                    credentials.Password,
+			DomainName:                  credentials.DomainName,
+			DomainID:                    credentials.DomainID,
+			ProjectName:                 credentials.TenantName,
+			ProjectID:                   credentials.TenantID,
+			UserDomainName:              credentials.UserDomainName,
+			UserDomainID:                credentials.UserDomainID,
+			ApplicationCredentialID:     credentials.ApplicationCredentialID,
+			ApplicationCredentialName:   credentials.ApplicationCredentialName,
+			ApplicationCredentialSecret: credentials.ApplicationCredentialSecret,
+		},
 	}
+	if clientOpts.AuthInfo.ApplicationCredentialSecret != "" {
+		clientOpts.AuthType = clientconfig.AuthV3ApplicationCredential
+	}
+	return clientOpts
 }
 
 // WithRegion returns an Option that can modify the region a client targets.
@@ -167,32 +236,64 @@ func WithRegion(region string) Option {
 	}
 }
 
-// Compute returns a client for OpenStack's Nova service.
-func (f *Factory) Compute(opts ...Option) (Compute, error) {
+// WithInterface returns an Option that selects the interface of the endpoints in the service catalog, i.e. "public",
+// "internal" or "admin".
+func WithInterface(endpointInterface string) Option {
+	return func(opts gophercloud.EndpointOpts) gophercloud.EndpointOpts {
+		opts.Availability = gophercloud.Availability(endpointInterface)
+		return opts
+	}
+}
+
+// endpointOpts applies the options of the Factory and the given ones.
+func (f *Factory) endpointOpts(opts ...Option) gophercloud.EndpointOpts {
 	eo := gophercloud.EndpointOpts{}
+	for _, opt := range f.options {
+		eo = opt(eo)
+	}
 	for _, opt := range opts {
 		eo = opt(eo)
 	}
+	return eo
+}
+
+// newServiceClient creates a service client. If an endpoint is given, it is used instead of locating the endpoint in the
+// service catalog.
+func newServiceClient(providerClient *gophercloud.ProviderClient, eo gophercloud.EndpointOpts, endpoint string,
+	newClient func(*gophercloud.ProviderClient, gophercloud.EndpointOpts) (*gophercloud.ServiceClient, error)) (*gophercloud.ServiceClient, error) {
+	if endpoint == "" {
+		return newClient(providerClient, eo)
+	}
+
+	// the endpoint is located through a copy of the provider client, which is not used for any request
+	pc := *providerClient
+	pc.EndpointLocator = func(gophercloud.EndpointOpts) (string, error) {
+		return gophercloud.NormalizeURL(endpoint), nil
+	}
+	serviceClient, err := newClient(&pc, eo)
+	if err != nil {
+		return nil, err
+	}
+	serviceClient.ProviderClient = providerClient
+	return serviceClient, nil
+}
 
-	return newNovaV2(f.providerClient, eo)
+// Compute returns a client for OpenStack's Nova service.
+func (f *Factory) Compute(opts ...Option) (Compute, error) {
+	return newNovaV2(f.providerClient, f.endpointOpts(opts...), f.endpointOverrides[computeServiceType], f.microversions)
 }
 
 // Network returns a client for OpenStack's Neutron service.
 func (f *Factory) Network(opts ...Option) (Network, error) {
-	eo := gophercloud.EndpointOpts{}
-	for _, opt := range opts {
-		eo = opt(eo)
-	}
-
-	return newNeutronV2(f.providerClient, eo)
+	return newNeutronV2(f.providerClient, f.endpointOpts(opts...), f.endpointOverrides[networkServiceType])
 }
 
 // Storage returns a client for OpenStack's Cinder service.
 func (f *Factory) Storage(opts ...Option) (Storage, error) {
-	eo := gophercloud.EndpointOpts{}
-	for _, opt := range opts {
-		eo = opt(eo)
-	}
+	return newCinderV3(f.providerClient, f.endpointOpts(opts...), f.endpointOverrides[volumeServiceType], f.microversions)
+}
 
-	return newCinderV3(f.providerClient, eo)
+// Image returns a client for OpenStack's Glance service.
+func (f *Factory) Image(opts ...Option) (Image, error) {
+	return newGlanceV2(f.providerClient, f.endpointOpts(opts...), f.endpointOverrides[imageServiceType])
 }
@@ -0,0 +1,49 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack"
+	"github.com/gophercloud/gophercloud/openstack/imageservice/v2/images"
+)
+
+const (
+	glanceService = "glance"
+)
+
+var _ Image = &glanceV2{}
+
+type glanceV2 struct {
+	serviceClient *gophercloud.ServiceClient
+}
+
+func newGlanceV2(providerClient *gophercloud.ProviderClient, eo gophercloud.EndpointOpts, endpoint string) (*glanceV2, error) {
+	image, err := newServiceClient(providerClient, eo, endpoint, openstack.NewImageServiceV2)
+	if err != nil {
+		return nil, fmt.Errorf("could not initialize image client: %v", err)
+	}
+
+	return &glanceV2{
+		serviceClient: image,
+	}, nil
+}
+
+// ListImages lists all images.
+func (c *glanceV2) ListImages(ctx context.Context, opts images.ListOptsBuilder) ([]images.Image, error) {
+	start := time.Now()
+	pages, err := images.List(withContext(ctx, c.serviceClient), opts).AllPages()
+	onCall(glanceService, "ListImages", start, err)
+	if err != nil {
+		onFailure(glanceService)
+		return nil, withRequestID(err)
+	}
+
+	return images.ExtractImages(pages)
+}
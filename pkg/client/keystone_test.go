@@ -0,0 +1,178 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// fakeKeystone is a minimal Keystone v3 identity service, which issues project scoped tokens for accepted passwords and
+// application credential secrets, and exchanges accepted OIDC access tokens for unscoped tokens.
+type fakeKeystone struct {
+	server    *httptest.Server
+	projectID string
+
+	mutex sync.Mutex
+	// accepted are the passwords and application credential secrets tokens are issued for.
+	accepted sets.Set[string]
+	// accessTokens are the OIDC access tokens accepted by the identity provider.
+	accessTokens sets.Set[string]
+	// unscopedTokens are the tokens issued by the identity provider, which can be exchanged for scoped tokens.
+	unscopedTokens sets.Set[string]
+	// tokenLifetime is the lifetime of the issued scoped tokens.
+	tokenLifetime time.Duration
+	// beforeAuth is invoked with the password or application credential secret before a token request is answered.
+	beforeAuth func(secret string)
+	// authRequests are the passwords, application credential secrets or token IDs of all token requests.
+	authRequests []string
+	issued       int
+}
+
+func newFakeKeystone() *fakeKeystone {
+	k := &fakeKeystone{
+		projectID:      "project-id",
+		accepted:       sets.New[string](),
+		accessTokens:   sets.New[string](),
+		unscopedTokens: sets.New[string](),
+		tokenLifetime:  time.Hour,
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v3/auth/tokens", k.createToken)
+	mux.HandleFunc("POST /v3/OS-FEDERATION/identity_providers/{idp}/protocols/{protocol}/auth", k.federatedAuth)
+	k.server = httptest.NewServer(mux)
+	return k
+}
+
+// authURL returns the identity endpoint of the fake Keystone.
+func (k *fakeKeystone) authURL() string {
+	return k.server.URL + "/v3"
+}
+
+func (k *fakeKeystone) close() {
+	k.server.Close()
+}
+
+// requests returns the passwords, application credential secrets or token IDs of all token requests.
+func (k *fakeKeystone) requests() []string {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+	return append([]string(nil), k.authRequests...)
+}
+
+func (k *fakeKeystone) createToken(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Auth struct {
+			Identity struct {
+				Password struct {
+					User struct {
+						Password string `json:"password"`
+					} `json:"user"`
+				} `json:"password"`
+				ApplicationCredential struct {
+					Secret string `json:"secret"`
+				} `json:"application_credential"`
+				Token struct {
+					ID string `json:"id"`
+				} `json:"token"`
+			} `json:"identity"`
+		} `json:"auth"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	identity := body.Auth.Identity
+	secret := identity.Password.User.Password + identity.ApplicationCredential.Secret + identity.Token.ID
+
+	k.mutex.Lock()
+	k.authRequests = append(k.authRequests, secret)
+	beforeAuth := k.beforeAuth
+	k.mutex.Unlock()
+	if beforeAuth != nil {
+		beforeAuth(secret)
+	}
+
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+	if !k.accepted.Has(secret) && !k.unscopedTokens.Has(secret) {
+		http.Error(w, `{"error": {"code": 401, "message": "The request you have made requires authentication."}}`, http.StatusUnauthorized)
+		return
+	}
+
+	k.issued++
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Subject-Token", fmt.Sprintf("token-%d", k.issued))
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"token": map[string]interface{}{
+			"expires_at": time.Now().Add(k.tokenLifetime).UTC().Format(time.RFC3339),
+			"project":    map[string]string{"id": k.projectID, "name": "project"},
+			"catalog": []interface{}{
+				k.catalogEntry("compute", "/compute/v2.1"),
+				k.catalogEntry("network", "/network"),
+				k.catalogEntry("volumev3", "/volume/v3/"+k.projectID),
+				k.catalogEntry("image", "/image"),
+			},
+		},
+	})
+}
+
+func (k *fakeKeystone) catalogEntry(serviceType, path string) map[string]interface{} {
+	return map[string]interface{}{
+		"type": serviceType,
+		"endpoints": []interface{}{
+			map[string]string{"interface": "public", "region_id": "region", "url": k.server.URL + path},
+		},
+	}
+}
+
+func (k *fakeKeystone) federatedAuth(w http.ResponseWriter, r *http.Request) {
+	accessToken, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+	if !k.accessTokens.Has(accessToken) {
+		http.Error(w, `{"error": {"code": 401, "message": "The request you have made requires authentication."}}`, http.StatusUnauthorized)
+		return
+	}
+
+	k.issued++
+	token := fmt.Sprintf("unscoped-%s-%s-%d", r.PathValue("idp"), r.PathValue("protocol"), k.issued)
+	k.unscopedTokens.Insert(token)
+	w.Header().Set("X-Subject-Token", token)
+	w.WriteHeader(http.StatusCreated)
+}
+
+// applicationCredentialSecret returns the secret data to authenticate at the fake Keystone with an application
+// credential.
+func (k *fakeKeystone) applicationCredentialSecret(secret string) map[string][]byte {
+	return map[string][]byte{
+		"authURL":                     []byte(k.authURL()),
+		"applicationCredentialID":     []byte("app-id"),
+		"applicationCredentialSecret": []byte(secret),
+	}
+}
+
+// providerClient returns a provider client authenticated at the fake Keystone with an application credential.
+func (k *fakeKeystone) providerClient(secret string) *gophercloud.ProviderClient {
+	provider, err := openstack.AuthenticatedClient(gophercloud.AuthOptions{
+		IdentityEndpoint:            k.authURL(),
+		ApplicationCredentialID:     "app-id",
+		ApplicationCredentialSecret: secret,
+	})
+	Expect(err).NotTo(HaveOccurred())
+	return provider
+}
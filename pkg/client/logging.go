@@ -0,0 +1,177 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// logVerbosity is the verbosity at which requests and responses are logged.
+	logVerbosity = 6
+	// redacted replaces the values of sensitive headers and fields.
+	redacted = "***"
+)
+
+var (
+	// sensitiveHeaders are the lower-case names of the headers whose values are redacted.
+	sensitiveHeaders = sets.New("authorization", "x-auth-token", "x-subject-token", "x-service-token", "x-auth-key", "set-cookie")
+	// sensitiveFields are the lower-case names of the JSON fields whose values are redacted, unless they are objects or
+	// arrays, which are redacted field by field instead. Extension prefixes like "OS-EXT-SRV-ATTR:" are ignored.
+	sensitiveFields = sets.New("password", "secret", "user_data", "token", "adminpass")
+	// omittedFields are the names of the JSON fields which are too large to be logged.
+	omittedFields = sets.New("catalog")
+)
+
+// loggingTransport is a http.RoundTripper, which logs requests and responses including their headers and JSON bodies.
+// Credentials, tokens and user data are redacted, so that the logs can be shared.
+type loggingTransport struct {
+	next http.RoundTripper
+}
+
+func newLoggingTransport(next http.RoundTripper) *loggingTransport {
+	return &loggingTransport{
+		next: next,
+	}
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// the verbosity is checked for every request, since it can be changed at runtime
+	if !klog.V(logVerbosity).Enabled() {
+		return t.next.RoundTrip(req)
+	}
+
+	klog.Infof("OpenStack request: %s %s", req.Method, req.URL.Redacted())
+	klog.Infof("OpenStack request headers:\n%s", formatHeaders(req.Header))
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err := io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		// the request must not be modified, so the body is replaced on a copy
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		logBody("OpenStack request body", req.Header, body)
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		klog.Infof("OpenStack request failed: %v", err)
+		return nil, err
+	}
+
+	klog.Infof("OpenStack response: %d", resp.StatusCode)
+	klog.Infof("OpenStack response headers:\n%s", formatHeaders(resp.Header))
+	if !isJSON(resp.Header) {
+		return resp, nil
+	}
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	logBody("OpenStack response body", resp.Header, body)
+	return resp, nil
+}
+
+// formatHeaders returns the sorted headers one per line with the values of sensitive headers redacted.
+func formatHeaders(header http.Header) string {
+	lines := make([]string, 0, len(header))
+	for name, values := range header {
+		value := strings.Join(values, " ")
+		if sensitiveHeaders.Has(strings.ToLower(name)) {
+			value = redacted
+		}
+		lines = append(lines, name+": "+value)
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "\n")
+}
+
+// logBody logs the body if it is JSON with the values of sensitive fields redacted.
+func logBody(prefix string, header http.Header, body []byte) {
+	if len(body) == 0 {
+		return
+	}
+	if !isJSON(header) {
+		klog.Infof("%s is not logged, because it is not JSON", prefix)
+		return
+	}
+
+	var data interface{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		klog.Infof("%s is not logged, because it is malformed: %v", prefix, err)
+		return
+	}
+	formatted, err := json.MarshalIndent(redactJSON(data, ""), "", "  ")
+	if err != nil {
+		klog.Infof("%s is not logged: %v", prefix, err)
+		return
+	}
+	klog.Infof("%s: %s", prefix, formatted)
+}
+
+func isJSON(header http.Header) bool {
+	mediaType, _, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json") || strings.HasSuffix(mediaType, "-json-patch")
+}
+
+// redactJSON redacts the values of sensitive fields in the unmarshalled JSON value. The field is the name of the field
+// the value belongs to.
+func redactJSON(value interface{}, field string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for name, child := range v {
+			key := fieldKey(name)
+			switch {
+			case omittedFields.Has(key):
+				v[name] = "..."
+			case field == "token" && key == "id":
+				// the ID of a token object, e.g. when authenticating with a token
+				v[name] = redacted
+			case sensitiveFields.Has(key) && !isComposite(child):
+				v[name] = redacted
+			default:
+				v[name] = redactJSON(child, key)
+			}
+		}
+	case []interface{}:
+		for i, child := range v {
+			v[i] = redactJSON(child, field)
+		}
+	}
+	return value
+}
+
+// fieldKey returns the lower-case name of the field without extension prefix.
+func fieldKey(name string) string {
+	if i := strings.LastIndex(name, ":"); i >= 0 {
+		name = name[i+1:]
+	}
+	return strings.ToLower(name)
+}
+
+func isComposite(value interface{}) bool {
+	switch value.(type) {
+	case map[string]interface{}, []interface{}:
+		return true
+	}
+	return false
+}
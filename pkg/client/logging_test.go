@@ -0,0 +1,148 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/klog/v2"
+)
+
+var _ = Describe("Logging", func() {
+	DescribeTable("#redactJSON",
+		func(in, expected string) {
+			var data interface{}
+			Expect(json.Unmarshal([]byte(in), &data)).To(Succeed())
+			Expect(json.Marshal(redactJSON(data, ""))).To(MatchJSON(expected))
+		},
+		Entry("password",
+			`{"auth": {"identity": {"methods": ["password"], "password": {"user": {"name": "admin", "password": "s3cr3t"}}}}}`,
+			`{"auth": {"identity": {"methods": ["password"], "password": {"user": {"name": "admin", "password": "***"}}}}}`),
+		Entry("application credential secret",
+			`{"auth": {"identity": {"application_credential": {"id": "app-id", "secret": "s3cr3t"}}}}`,
+			`{"auth": {"identity": {"application_credential": {"id": "app-id", "secret": "***"}}}}`),
+		Entry("token ID",
+			`{"auth": {"identity": {"methods": ["token"], "token": {"id": "gAAAA"}}}}`,
+			`{"auth": {"identity": {"methods": ["token"], "token": {"id": "***"}}}}`),
+		Entry("token string",
+			`{"access": {"token": "gAAAA", "id": "access-id"}}`,
+			`{"access": {"token": "***", "id": "access-id"}}`),
+		Entry("user data and admin password of a server",
+			`{"server": {"name": "machine", "user_data": "I2Nsb3VkLWNvbmZpZw==", "adminPass": "s3cr3t"}}`,
+			`{"server": {"name": "machine", "user_data": "***", "adminPass": "***"}}`),
+		Entry("extension prefixed fields",
+			`{"server": {"OS-EXT-SRV-ATTR:user_data": "I2Nsb3VkLWNvbmZpZw==", "OS-EXT-STS:vm_state": "active"}}`,
+			`{"server": {"OS-EXT-SRV-ATTR:user_data": "***", "OS-EXT-STS:vm_state": "active"}}`),
+		Entry("arrays of objects",
+			`{"servers": [{"name": "a", "adminPass": "s3cr3t"}, {"name": "b", "adminPass": "s3cr3t"}]}`,
+			`{"servers": [{"name": "a", "adminPass": "***"}, {"name": "b", "adminPass": "***"}]}`),
+		Entry("sensitive arrays field by field",
+			`{"secret": [{"name": "a", "password": "s3cr3t"}, "plain"]}`,
+			`{"secret": [{"name": "a", "password": "***"}, "plain"]}`),
+		Entry("service catalog",
+			`{"token": {"catalog": [{"type": "compute"}], "expires_at": "2026-10-16T12:00:00Z"}}`,
+			`{"token": {"catalog": "...", "expires_at": "2026-10-16T12:00:00Z"}}`),
+		Entry("insensitive values",
+			`{"server": {"name": "machine", "metadata": {"role": "worker"}, "networks": ["a", "b"]}}`,
+			`{"server": {"name": "machine", "metadata": {"role": "worker"}, "networks": ["a", "b"]}}`),
+	)
+
+	DescribeTable("#formatHeaders",
+		func(header http.Header, expected string) {
+			Expect(formatHeaders(header)).To(Equal(expected))
+		},
+		Entry("no headers", http.Header{}, ""),
+		Entry("auth token", http.Header{"X-Auth-Token": {"gAAAA"}, "Accept": {"application/json"}}, "Accept: application/json\nX-Auth-Token: ***"),
+		Entry("subject token", http.Header{"X-Subject-Token": {"gAAAA"}, "Content-Type": {"application/json"}}, "Content-Type: application/json\nX-Subject-Token: ***"),
+		Entry("authorization", http.Header{"Authorization": {"Bearer eyJ"}}, "Authorization: ***"),
+		Entry("multiple values", http.Header{"Vary": {"X-Auth-Token", "Accept"}}, "Vary: X-Auth-Token Accept"),
+	)
+
+	Describe("#loggingTransport", func() {
+		var (
+			server *httptest.Server
+			logs   *bytes.Buffer
+		)
+
+		setKlogFlags := func(values map[string]string) {
+			fs := flag.NewFlagSet("klog", flag.ContinueOnError)
+			klog.InitFlags(fs)
+			for name, value := range values {
+				Expect(fs.Set(name, value)).To(Succeed())
+			}
+		}
+
+		BeforeEach(func() {
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				w.Header().Set("Content-Type", "application/json")
+				w.Header().Set("X-Subject-Token", "subject-token")
+				w.WriteHeader(http.StatusCreated)
+				_, _ = w.Write([]byte(`{"token": {"methods": ["password"], "echo": ` + string(body) + `}}`))
+			}))
+			DeferCleanup(server.Close)
+
+			logs = &bytes.Buffer{}
+			klog.SetOutput(logs)
+			setKlogFlags(map[string]string{"logtostderr": "false", "alsologtostderr": "false", "v": "6"})
+			DeferCleanup(func() {
+				setKlogFlags(map[string]string{"logtostderr": "true", "v": "0"})
+				klog.SetOutput(io.Discard)
+			})
+		})
+
+		It("should log requests and responses with credentials redacted", func() {
+			client := &http.Client{Transport: newLoggingTransport(http.DefaultTransport)}
+			req, err := http.NewRequest(http.MethodPost, server.URL+"/v3/auth/tokens", strings.NewReader(`{"auth": {"password": "s3cr3t"}}`))
+			Expect(err).NotTo(HaveOccurred())
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("X-Auth-Token", "auth-token")
+
+			resp, err := client.Do(req)
+			Expect(err).NotTo(HaveOccurred())
+			defer resp.Body.Close()
+
+			// the bodies are passed on unmodified
+			body, err := io.ReadAll(resp.Body)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(body).To(MatchJSON(`{"token": {"methods": ["password"], "echo": {"auth": {"password": "s3cr3t"}}}}`))
+			Expect(resp.Header.Get("X-Subject-Token")).To(Equal("subject-token"))
+
+			klog.Flush()
+			Expect(logs.String()).To(And(
+				ContainSubstring("OpenStack request: POST "+server.URL+"/v3/auth/tokens"),
+				ContainSubstring("X-Auth-Token: ***"),
+				ContainSubstring("X-Subject-Token: ***"),
+				ContainSubstring(`"password": "***"`),
+				ContainSubstring("OpenStack response: 201"),
+			))
+			Expect(logs.String()).NotTo(Or(
+				ContainSubstring("s3cr3t"),
+				ContainSubstring("auth-token"),
+				ContainSubstring("subject-token"),
+			))
+		})
+
+		It("should not log anything below the verbosity", func() {
+			setKlogFlags(map[string]string{"v": "5"})
+
+			client := &http.Client{Transport: newLoggingTransport(http.DefaultTransport)}
+			resp, err := client.Post(server.URL+"/v3/auth/tokens", "application/json", strings.NewReader(`{}`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.Body.Close()).To(Succeed())
+
+			klog.Flush()
+			Expect(logs.String()).To(BeEmpty())
+		})
+	})
+})
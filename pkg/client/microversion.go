@@ -0,0 +1,145 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gophercloud/gophercloud"
+)
+
+var (
+	// computeFeatureMicroversions are the minimum compute API microversions required by the optional features.
+	computeFeatureMicroversions = map[Feature]string{
+		FeatureServerDescription: "2.19",
+		FeatureServerTags:        "2.26",
+		FeatureServerCreateTags:  "2.52",
+		FeatureMultiattach:       "2.60",
+		FeatureServerHostname:    "2.90",
+	}
+	// storageFeatureMicroversions are the minimum block storage API microversions required by the optional features.
+	storageFeatureMicroversions = map[Feature]string{
+		FeatureMultiattach: "3.50",
+	}
+)
+
+// failedDiscoveryTTL is the time a failed microversion discovery is cached, so that clients created in the meantime fall
+// back to the base microversion right away instead of repeating the discovery.
+const failedDiscoveryTTL = time.Minute
+
+// microversionCache caches the maximum API microversion supported per service endpoint, so that it is only discovered
+// once for all clients created by a Factory.
+type microversionCache struct {
+	// mutex guards entries, the discovery itself is serialized per endpoint by the mutex of the entry.
+	mutex   sync.Mutex
+	entries map[string]*microversionCacheEntry
+	now     func() time.Time
+}
+
+type microversionCacheEntry struct {
+	mutex      sync.Mutex
+	discovered bool
+	version    string
+	err        error
+	// retryAfter is the time after which a failed discovery is repeated.
+	retryAfter time.Time
+}
+
+func newMicroversionCache() *microversionCache {
+	return &microversionCache{
+		entries: map[string]*microversionCacheEntry{},
+		now:     time.Now,
+	}
+}
+
+// get returns the cached maximum microversion of the endpoint, or invokes discover and caches its result. Failed
+// discoveries are cached for failedDiscoveryTTL and repeated by the first call afterwards. Concurrent calls for the same
+// endpoint wait for a running discovery, calls for other endpoints are not blocked by it.
+func (c *microversionCache) get(endpoint string, discover func() (string, error)) (string, error) {
+	c.mutex.Lock()
+	entry, ok := c.entries[endpoint]
+	if !ok {
+		entry = &microversionCacheEntry{}
+		c.entries[endpoint] = entry
+	}
+	c.mutex.Unlock()
+
+	entry.mutex.Lock()
+	defer entry.mutex.Unlock()
+
+	if entry.discovered && (entry.err == nil || c.now().Before(entry.retryAfter)) {
+		return entry.version, entry.err
+	}
+	entry.version, entry.err = discover()
+	entry.discovered = true
+	if entry.err != nil {
+		entry.retryAfter = c.now().Add(failedDiscoveryTTL)
+	}
+	return entry.version, entry.err
+}
+
+// featureMicroversions resolves the minimum microversions of the features against the maximum microversion of an
+// endpoint. Features the endpoint does not support are omitted.
+func featureMicroversions(required map[Feature]string, maxMicroversion string) map[Feature]string {
+	supported := map[Feature]string{}
+	for feature, minimum := range required {
+		if microversionAtLeast(maxMicroversion, minimum) {
+			supported[feature] = minimum
+		}
+	}
+	return supported
+}
+
+// withMicroversion returns a copy of the service client requesting the highest of the given microversions. The service
+// client is returned as is if no microversion is given.
+func withMicroversion(serviceClient *gophercloud.ServiceClient, microversions ...string) *gophercloud.ServiceClient {
+	highest := ""
+	for _, microversion := range microversions {
+		if highest == "" || microversionAtLeast(microversion, highest) {
+			highest = microversion
+		}
+	}
+	if highest == "" {
+		return serviceClient
+	}
+
+	sc := *serviceClient
+	sc.Microversion = highest
+	return &sc
+}
+
+// microversionAtLeast reports whether the microversion is at least the given minimum. Malformed or empty microversions
+// are treated as not supporting any microversion.
+func microversionAtLeast(version, minimum string) bool {
+	major, minor, err := parseMicroversion(version)
+	if err != nil {
+		return false
+	}
+	minMajor, minMinor, err := parseMicroversion(minimum)
+	if err != nil {
+		return false
+	}
+	return major > minMajor || (major == minMajor && minor >= minMinor)
+}
+
+func parseMicroversion(version string) (int, int, error) {
+	majorStr, minorStr, ok := strings.Cut(version, ".")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid microversion %q", version)
+	}
+	major, err := strconv.Atoi(majorStr)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid microversion %q: %w", version, err)
+	}
+	minor, err := strconv.Atoi(minorStr)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid microversion %q: %w", version, err)
+	}
+	return major, minor, nil
+}
@@ -0,0 +1,151 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Microversions", func() {
+	Describe("#microversionCache", func() {
+		var (
+			cache       *microversionCache
+			now         time.Time
+			discoveries int
+		)
+
+		BeforeEach(func() {
+			now = time.Now()
+			cache = newMicroversionCache()
+			cache.now = func() time.Time { return now }
+			discoveries = 0
+		})
+
+		discover := func(version string, err error) func() (string, error) {
+			return func() (string, error) {
+				discoveries++
+				return version, err
+			}
+		}
+
+		It("should discover the microversion of an endpoint once", func() {
+			for range 3 {
+				version, err := cache.get("compute", discover("2.95", nil))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(version).To(Equal("2.95"))
+			}
+			Expect(discoveries).To(Equal(1))
+		})
+
+		It("should cache a failed discovery for a short time", func() {
+			failure := errors.New("version document not found")
+			_, err := cache.get("compute", discover("", failure))
+			Expect(err).To(MatchError(failure))
+
+			_, err = cache.get("compute", discover("2.95", nil))
+			Expect(err).To(MatchError(failure))
+			Expect(discoveries).To(Equal(1))
+
+			now = now.Add(failedDiscoveryTTL + time.Second)
+			version, err := cache.get("compute", discover("2.95", nil))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(version).To(Equal("2.95"))
+			Expect(discoveries).To(Equal(2))
+		})
+
+		It("should not block other endpoints during a discovery", func() {
+			release := make(chan struct{})
+			DeferCleanup(func() { close(release) })
+			started := make(chan struct{})
+
+			go func() {
+				defer GinkgoRecover()
+				_, _ = cache.get("slow", func() (string, error) {
+					close(started)
+					<-release
+					return "2.1", nil
+				})
+			}()
+			Eventually(started).Should(BeClosed())
+
+			done := make(chan string)
+			go func() {
+				version, _ := cache.get("compute", func() (string, error) { return "2.95", nil })
+				done <- version
+			}()
+			Eventually(done).Should(Receive(Equal("2.95")))
+		})
+	})
+
+	Describe("#featureMicroversions", func() {
+		It("should only return the features supported by the maximum microversion", func() {
+			Expect(featureMicroversions(computeFeatureMicroversions, "2.90")).To(Equal(map[Feature]string{
+				FeatureServerDescription: "2.19",
+				FeatureServerTags:        "2.26",
+				FeatureServerCreateTags:  "2.52",
+				FeatureMultiattach:       "2.60",
+				FeatureServerHostname:    "2.90",
+			}))
+			Expect(featureMicroversions(computeFeatureMicroversions, "2.60")).To(Equal(map[Feature]string{
+				FeatureServerDescription: "2.19",
+				FeatureServerTags:        "2.26",
+				FeatureServerCreateTags:  "2.52",
+				FeatureMultiattach:       "2.60",
+			}))
+			Expect(featureMicroversions(computeFeatureMicroversions, "2.19")).To(Equal(map[Feature]string{
+				FeatureServerDescription: "2.19",
+			}))
+			Expect(featureMicroversions(computeFeatureMicroversions, "")).To(BeEmpty())
+		})
+	})
+
+	Describe("#createServerClient", func() {
+		createOpts := func(description, hostname string) servers.CreateOptsBuilder {
+			return ServerDetailsCreateOptsExt{
+				CreateOptsBuilder: servers.CreateOpts{Name: "machine", FlavorRef: "flavor"},
+				Description:       description,
+				Hostname:          hostname,
+			}
+		}
+
+		It("should request the microversion required by the description and hostname", func() {
+			nova := &novaV2{serviceClient: &gophercloud.ServiceClient{}, features: featureMicroversions(computeFeatureMicroversions, "2.95")}
+
+			serviceClient, err := nova.createServerClient(createOpts("machine of the cluster", ""))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(serviceClient.Microversion).To(Equal("2.19"))
+
+			serviceClient, err = nova.createServerClient(createOpts("machine of the cluster", "machine"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(serviceClient.Microversion).To(Equal("2.90"))
+
+			serviceClient, err = nova.createServerClient(createOpts("", ""))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(serviceClient.Microversion).To(BeEmpty())
+		})
+
+		It("should fail if the hostname is not supported", func() {
+			nova := &novaV2{serviceClient: &gophercloud.ServiceClient{}, features: featureMicroversions(computeFeatureMicroversions, "2.60")}
+
+			_, err := nova.createServerClient(createOpts("", "machine"))
+			Expect(err).To(MatchError(ContainSubstring(string(FeatureServerHostname))))
+		})
+	})
+
+	Describe("#withMicroversion", func() {
+		It("should request the highest microversion", func() {
+			serviceClient := &gophercloud.ServiceClient{}
+			Expect(withMicroversion(serviceClient, "2.26", "2.60", "2.52").Microversion).To(Equal("2.60"))
+			Expect(withMicroversion(serviceClient)).To(BeIdenticalTo(serviceClient))
+			Expect(serviceClient.Microversion).To(BeEmpty())
+		})
+	})
+})
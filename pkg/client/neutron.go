@@ -5,18 +5,23 @@
 package client
 
 import (
+	"context"
 	"fmt"
+	"time"
 
-	"github.com/gardener/machine-controller-manager/pkg/util/provider/metrics"
 	"github.com/gophercloud/gophercloud"
 	"github.com/gophercloud/gophercloud/openstack"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/attributestags"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/floatingips"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/ports"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/subnets"
 	utilGroups "github.com/gophercloud/utils/openstack/networking/v2/extensions/security/groups"
 	utilNetworks "github.com/gophercloud/utils/openstack/networking/v2/networks"
 	utilPorts "github.com/gophercloud/utils/openstack/networking/v2/ports"
-	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	neutronService = "neutron"
 )
 
 var _ Network = &neutronV2{}
@@ -26,8 +31,8 @@ type neutronV2 struct {
 	serviceClient *gophercloud.ServiceClient
 }
 
-func newNeutronV2(providerClient *gophercloud.ProviderClient, eo gophercloud.EndpointOpts) (*neutronV2, error) {
-	nw, err := openstack.NewNetworkV2(providerClient, eo)
+func newNeutronV2(providerClient *gophercloud.ProviderClient, eo gophercloud.EndpointOpts, endpoint string) (*neutronV2, error) {
+	nw, err := newServiceClient(providerClient, eo, endpoint, openstack.NewNetworkV2)
 	if err != nil {
 		return nil, fmt.Errorf("could not initialize network client: %v", err)
 	}
@@ -37,115 +42,197 @@ func newNeutronV2(providerClient *gophercloud.ProviderClient, eo gophercloud.End
 }
 
 // GetSubnet fetches the subnet data from the supplied ID.
-func (n *neutronV2) GetSubnet(id string) (*subnets.Subnet, error) {
-	sn, err := subnets.Get(n.serviceClient, id).Extract()
+func (n *neutronV2) GetSubnet(ctx context.Context, id string) (*subnets.Subnet, error) {
+	start := time.Now()
+	sn, err := subnets.Get(withContext(ctx, n.serviceClient), id).Extract()
 
-	metrics.APIRequestCount.With(prometheus.Labels{"provider": "openstack", "service": "neutron"}).Inc()
+	onCall(neutronService, "GetSubnet", start, err)
 	if err != nil {
-		metrics.APIFailedRequestCount.With(prometheus.Labels{"provider": "openstack", "service": "neutron"}).Inc()
-		return nil, err
+		onFailure(neutronService)
+		return nil, withRequestID(err)
 	}
 	return sn, nil
 }
 
 // CreatePort creates a Neutron port.
-func (n *neutronV2) CreatePort(opts ports.CreateOptsBuilder) (*ports.Port, error) {
-	p, err := ports.Create(n.serviceClient, opts).Extract()
+func (n *neutronV2) CreatePort(ctx context.Context, opts ports.CreateOptsBuilder) (*ports.Port, error) {
+	start := time.Now()
+	p, err := ports.Create(withContext(ctx, n.serviceClient), opts).Extract()
 
-	metrics.APIRequestCount.With(prometheus.Labels{"provider": "openstack", "service": "neutron"}).Inc()
+	onCall(neutronService, "CreatePort", start, err)
 	if err != nil {
-		metrics.APIFailedRequestCount.With(prometheus.Labels{"provider": "openstack", "service": "neutron"}).Inc()
-		return nil, err
+		onFailure(neutronService)
+		return nil, withRequestID(err)
 	}
 	return p, nil
 }
 
 // ListPorts lists all ports.
-func (n *neutronV2) ListPorts(opts ports.ListOptsBuilder) ([]ports.Port, error) {
-	pages, err := ports.List(n.serviceClient, opts).AllPages()
-	metrics.APIRequestCount.With(prometheus.Labels{"provider": "openstack", "service": "neutron"}).Inc()
+func (n *neutronV2) ListPorts(ctx context.Context, opts ports.ListOptsBuilder) ([]ports.Port, error) {
+	start := time.Now()
+	pages, err := ports.List(withContext(ctx, n.serviceClient), opts).AllPages()
+	onCall(neutronService, "ListPorts", start, err)
 
 	if err != nil {
-		metrics.APIFailedRequestCount.With(prometheus.Labels{"provider": "openstack", "service": "neutron"}).Inc()
-		return nil, err
+		onFailure(neutronService)
+		return nil, withRequestID(err)
 	}
 
 	return ports.ExtractPorts(pages)
 }
 
 // UpdatePort updates the port from the supplied ID.
-func (n *neutronV2) UpdatePort(id string, opts ports.UpdateOptsBuilder) error {
-	_, err := ports.Update(n.serviceClient, id, opts).Extract()
-	metrics.APIRequestCount.With(prometheus.Labels{"provider": "openstack", "service": "neutron"}).Inc()
+func (n *neutronV2) UpdatePort(ctx context.Context, id string, opts ports.UpdateOptsBuilder) error {
+	start := time.Now()
+	_, err := ports.Update(withContext(ctx, n.serviceClient), id, opts).Extract()
+	onCall(neutronService, "UpdatePort", start, err)
 
 	if err != nil {
 		// skip registering not found errors as API errors
 		if !IsNotFoundError(err) {
-			metrics.APIFailedRequestCount.With(prometheus.Labels{"provider": "openstack", "service": "neutron"}).Inc()
+			onFailure(neutronService)
 		}
-		return err
+		return withRequestID(err)
 	}
 	return nil
 }
 
 // DeletePort deletes the port from the supplied ID.
-func (n *neutronV2) DeletePort(id string) error {
-	err := ports.Delete(n.serviceClient, id).ExtractErr()
+func (n *neutronV2) DeletePort(ctx context.Context, id string) error {
+	start := time.Now()
+	err := ports.Delete(withContext(ctx, n.serviceClient), id).ExtractErr()
 
-	metrics.APIRequestCount.With(prometheus.Labels{"provider": "openstack", "service": "neutron"}).Inc()
+	onCall(neutronService, "DeletePort", start, err)
 	if err != nil && !IsNotFoundError(err) {
-		metrics.APIFailedRequestCount.With(prometheus.Labels{"provider": "openstack", "service": "neutron"}).Inc()
-		return err
+		onFailure(neutronService)
+		return withRequestID(err)
 	}
 	return nil
 }
 
 // NetworkIDFromName resolves the given network name to a unique ID.
-func (n *neutronV2) NetworkIDFromName(name string) (string, error) {
-	id, err := utilNetworks.IDFromName(n.serviceClient, name)
+func (n *neutronV2) NetworkIDFromName(ctx context.Context, name string) (string, error) {
+	start := time.Now()
+	id, err := utilNetworks.IDFromName(withContext(ctx, n.serviceClient), name)
 
-	metrics.APIRequestCount.With(prometheus.Labels{"provider": "openstack", "service": "neutron"}).Inc()
+	onCall(neutronService, "NetworkIDFromName", start, err)
 	if err != nil {
-		metrics.APIFailedRequestCount.With(prometheus.Labels{"provider": "openstack", "service": "neutron"}).Inc()
-		return "", err
+		onFailure(neutronService)
+		return "", withRequestID(err)
 	}
 	return id, nil
 }
 
 // GroupIDFromName resolves the given security group name to a unique ID.
-func (n *neutronV2) GroupIDFromName(name string) (string, error) {
-	id, err := utilGroups.IDFromName(n.serviceClient, name)
+func (n *neutronV2) GroupIDFromName(ctx context.Context, name string) (string, error) {
+	start := time.Now()
+	id, err := utilGroups.IDFromName(withContext(ctx, n.serviceClient), name)
 
-	metrics.APIRequestCount.With(prometheus.Labels{"provider": "openstack", "service": "neutron"}).Inc()
+	onCall(neutronService, "GroupIDFromName", start, err)
 	if err != nil {
-		metrics.APIFailedRequestCount.With(prometheus.Labels{"provider": "openstack", "service": "neutron"}).Inc()
-		return "", err
+		onFailure(neutronService)
+		return "", withRequestID(err)
 	}
 	return id, nil
 }
 
 // PortIDFromName resolves the given port name to a unique ID.
-func (n *neutronV2) PortIDFromName(name string) (string, error) {
-	id, err := utilPorts.IDFromName(n.serviceClient, name)
-	metrics.APIRequestCount.With(prometheus.Labels{"provider": "openstack", "service": "neutron"}).Inc()
+func (n *neutronV2) PortIDFromName(ctx context.Context, name string) (string, error) {
+	start := time.Now()
+	id, err := utilPorts.IDFromName(withContext(ctx, n.serviceClient), name)
+	onCall(neutronService, "PortIDFromName", start, err)
 
 	if err != nil {
-		metrics.APIFailedRequestCount.With(prometheus.Labels{"provider": "openstack", "service": "neutron"}).Inc()
-		return "", err
+		onFailure(neutronService)
+		return "", withRequestID(err)
 	}
 	return id, nil
 }
 
-func (n *neutronV2) TagPort(id string, tags []string) error {
+// TagPort tags a port with the specified labels.
+func (n *neutronV2) TagPort(ctx context.Context, id string, tags []string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	tagOpts := attributestags.ReplaceAllOpts{Tags: tags}
+	start := time.Now()
+	_, err := attributestags.ReplaceAll(withContext(ctx, n.serviceClient), "ports", id, tagOpts).Extract()
+	onCall(neutronService, "TagPort", start, err)
+	if err != nil {
+		onFailure(neutronService)
+		return withRequestID(err)
+	}
+	return nil
+}
+
+// CreateFloatingIP creates a floating IP.
+func (n *neutronV2) CreateFloatingIP(ctx context.Context, opts floatingips.CreateOptsBuilder) (*floatingips.FloatingIP, error) {
+	start := time.Now()
+	fip, err := floatingips.Create(withContext(ctx, n.serviceClient), opts).Extract()
+
+	onCall(neutronService, "CreateFloatingIP", start, err)
+	if err != nil {
+		onFailure(neutronService)
+		return nil, withRequestID(err)
+	}
+	return fip, nil
+}
+
+// ListFloatingIPs lists all floating IPs.
+func (n *neutronV2) ListFloatingIPs(ctx context.Context, opts floatingips.ListOptsBuilder) ([]floatingips.FloatingIP, error) {
+	start := time.Now()
+	pages, err := floatingips.List(withContext(ctx, n.serviceClient), opts).AllPages()
+	onCall(neutronService, "ListFloatingIPs", start, err)
+
+	if err != nil {
+		onFailure(neutronService)
+		return nil, withRequestID(err)
+	}
+
+	return floatingips.ExtractFloatingIPs(pages)
+}
+
+// UpdateFloatingIP updates the floating IP from the supplied ID.
+func (n *neutronV2) UpdateFloatingIP(ctx context.Context, id string, opts floatingips.UpdateOptsBuilder) error {
+	start := time.Now()
+	_, err := floatingips.Update(withContext(ctx, n.serviceClient), id, opts).Extract()
+	onCall(neutronService, "UpdateFloatingIP", start, err)
+
+	if err != nil {
+		// skip registering not found errors as API errors
+		if !IsNotFoundError(err) {
+			onFailure(neutronService)
+		}
+		return withRequestID(err)
+	}
+	return nil
+}
+
+// DeleteFloatingIP deletes the floating IP from the supplied ID.
+func (n *neutronV2) DeleteFloatingIP(ctx context.Context, id string) error {
+	start := time.Now()
+	err := floatingips.Delete(withContext(ctx, n.serviceClient), id).ExtractErr()
+
+	onCall(neutronService, "DeleteFloatingIP", start, err)
+	if err != nil && !IsNotFoundError(err) {
+		onFailure(neutronService)
+		return withRequestID(err)
+	}
+	return nil
+}
+
+// TagFloatingIP tags a floating IP with the specified labels.
+func (n *neutronV2) TagFloatingIP(ctx context.Context, id string, tags []string) error {
 	if len(tags) == 0 {
 		return nil
 	}
 	tagOpts := attributestags.ReplaceAllOpts{Tags: tags}
-	_, err := attributestags.ReplaceAll(n.serviceClient, "ports", id, tagOpts).Extract()
+	start := time.Now()
+	_, err := attributestags.ReplaceAll(withContext(ctx, n.serviceClient), "floatingips", id, tagOpts).Extract()
+	onCall(neutronService, "TagFloatingIP", start, err)
 	if err != nil {
-		metrics.APIFailedRequestCount.With(prometheus.Labels{"provider": "openstack", "service": "neutron"}).Inc()
-		return err
+		onFailure(neutronService)
+		return withRequestID(err)
 	}
-	metrics.APIRequestCount.With(prometheus.Labels{"provider": "openstack", "service": "neutron"}).Inc()
 	return nil
 }
@@ -5,19 +5,25 @@
 package client
 
 import (
+	"context"
 	"fmt"
+	"time"
 
-	"github.com/gardener/machine-controller-manager/pkg/util/provider/metrics"
 	"github.com/gophercloud/gophercloud"
 	"github.com/gophercloud/gophercloud/openstack"
+	"github.com/gophercloud/gophercloud/openstack/compute/apiversions"
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/bootfromvolume"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/servergroups"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/tags"
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
 	"github.com/gophercloud/utils/openstack/compute/v2/flavors"
 	"github.com/gophercloud/utils/openstack/imageservice/v2/images"
-	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/klog/v2"
 )
 
 const (
+	novaService = "nova"
+
 	// Server status source: https://docs.openstack.org/api-guide/compute/server_concepts.html
 
 	// ServerStatusActive indicates that the server is active.
@@ -28,6 +34,12 @@ const (
 	ServerStatusDeleted = "DELETED"
 	// ServerStatusError indicates that the server is in error.
 	ServerStatusError = "ERROR"
+	// ServerStatusShutoff indicates that the server was stopped and is not running.
+	ServerStatusShutoff = "SHUTOFF"
+
+	// serverGroupMicroversion is the minimum compute API microversion supporting the soft-affinity and
+	// soft-anti-affinity server group policies.
+	serverGroupMicroversion = "2.15"
 )
 
 var _ Compute = &novaV2{}
@@ -35,106 +47,274 @@ var _ Compute = &novaV2{}
 // novaV2 is a NovaV2 client implementing the Compute interface.
 type novaV2 struct {
 	serviceClient *gophercloud.ServiceClient
+	// features maps the supported optional features to the microversion they require
+	features map[Feature]string
 }
 
-func newNovaV2(providerClient *gophercloud.ProviderClient, eo gophercloud.EndpointOpts) (*novaV2, error) {
-	compute, err := openstack.NewComputeV2(providerClient, eo)
+func newNovaV2(providerClient *gophercloud.ProviderClient, eo gophercloud.EndpointOpts, endpoint string, microversions *microversionCache) (*novaV2, error) {
+	compute, err := newServiceClient(providerClient, eo, endpoint, openstack.NewComputeV2)
 	if err != nil {
 		return nil, fmt.Errorf("could not initialize compute client: %v", err)
 	}
 
-	return &novaV2{
+	c := &novaV2{
 		serviceClient: compute,
-	}, nil
+	}
+
+	maxMicroversion, err := microversions.get(compute.Endpoint, c.maxMicroversion)
+	if err != nil {
+		// older clouds may not expose the version document, fall back to the base API behavior
+		klog.Warningf("failed to discover compute API microversion of endpoint %q: %v", compute.Endpoint, err)
+	}
+	c.features = featureMicroversions(computeFeatureMicroversions, maxMicroversion)
+	return c, nil
 }
 
-// CreateServer creates a server.
-func (c *novaV2) CreateServer(opts servers.CreateOptsBuilder) (*servers.Server, error) {
-	server, err := servers.Create(c.serviceClient, opts).Extract()
+// maxMicroversion returns the maximum microversion supported by the compute API.
+func (c *novaV2) maxMicroversion() (string, error) {
+	start := time.Now()
+	version, err := apiversions.Get(c.serviceClient, "v2.1").Extract()
+
+	onCall(novaService, "GetAPIVersion", start, err)
+	if err != nil {
+		onFailure(novaService)
+		return "", withRequestID(err)
+	}
+	return version.Version, nil
+}
+
+// Supports reports whether the compute API supports the optional feature.
+func (c *novaV2) Supports(feature Feature) bool {
+	_, ok := c.features[feature]
+	return ok
+}
+
+// featureClient returns a service client requesting the minimum microversion required by the supported features among
+// the given ones. Unsupported features are ignored and the requests fall back to the base API behavior.
+func (c *novaV2) featureClient(features ...Feature) *gophercloud.ServiceClient {
+	var microversions []string
+	for _, feature := range features {
+		if microversion, ok := c.features[feature]; ok {
+			microversions = append(microversions, microversion)
+		}
+	}
+	return withMicroversion(c.serviceClient, microversions...)
+}
+
+// createServerClient returns a service client requesting the minimum microversion required by the optional fields set
+// in the server create request. It fails if a field requires a feature the compute API does not support.
+func (c *novaV2) createServerClient(opts servers.CreateOptsBuilder, features ...Feature) (*gophercloud.ServiceClient, error) {
+	body, err := opts.ToServerCreateMap()
+	if err != nil {
+		return nil, err
+	}
+	server, _ := body["server"].(map[string]interface{})
+	for field, feature := range map[string]Feature{"description": FeatureServerDescription, "hostname": FeatureServerHostname, "tags": FeatureServerCreateTags} {
+		if _, ok := server[field]; !ok {
+			continue
+		}
+		if !c.Supports(feature) {
+			return nil, fmt.Errorf("server field %q requires feature %s, which is not supported by the compute API", field, feature)
+		}
+		features = append(features, feature)
+	}
+	return c.featureClient(features...), nil
+}
 
-	metrics.APIRequestCount.With(prometheus.Labels{"provider": "openstack", "service": "nova"}).Inc()
+// CreateServer creates a server.
+func (c *novaV2) CreateServer(ctx context.Context, opts servers.CreateOptsBuilder) (*servers.Server, error) {
+	serviceClient, err := c.createServerClient(opts)
 	if err != nil {
-		metrics.APIFailedRequestCount.With(prometheus.Labels{"provider": "openstack", "service": "nova"}).Inc()
 		return nil, err
 	}
+	start := time.Now()
+	server, err := servers.Create(withContext(ctx, serviceClient), opts).Extract()
+
+	onCall(novaService, "CreateServer", start, err)
+	if err != nil {
+		onFailure(novaService)
+		return nil, withRequestID(err)
+	}
 
 	return server, nil
 }
 
 // BootFromVolume creates a server from a block device mapping.
-func (c *novaV2) BootFromVolume(opts servers.CreateOptsBuilder) (*servers.Server, error) {
-	server, err := bootfromvolume.Create(c.serviceClient, opts).Extract()
-
-	metrics.APIRequestCount.With(prometheus.Labels{"provider": "openstack", "service": "nova"}).Inc()
+func (c *novaV2) BootFromVolume(ctx context.Context, opts servers.CreateOptsBuilder) (*servers.Server, error) {
+	var features []Feature
+	// attaching multiattach volumes requires a minimum microversion
+	if _, ok := opts.(MultiattachCreateOptsExt); ok {
+		if !c.Supports(FeatureMultiattach) {
+			return nil, fmt.Errorf("attaching multiattach volumes requires feature %s, which is not supported by the compute API", FeatureMultiattach)
+		}
+		features = append(features, FeatureMultiattach)
+	}
+	serviceClient, err := c.createServerClient(opts, features...)
 	if err != nil {
-		metrics.APIFailedRequestCount.With(prometheus.Labels{"provider": "openstack", "service": "nova"}).Inc()
 		return nil, err
 	}
+	start := time.Now()
+	server, err := bootfromvolume.Create(withContext(ctx, serviceClient), opts).Extract()
+
+	onCall(novaService, "BootFromVolume", start, err)
+	if err != nil {
+		onFailure(novaService)
+		return nil, withRequestID(err)
+	}
 	return server, nil
 }
 
 // GetServer fetches server data from the supplied ID.
-func (c *novaV2) GetServer(id string) (*servers.Server, error) {
-	server, err := servers.Get(c.serviceClient, id).Extract()
+func (c *novaV2) GetServer(ctx context.Context, id string) (*servers.Server, error) {
+	start := time.Now()
+	server, err := servers.Get(withContext(ctx, c.featureClient(FeatureServerTags)), id).Extract()
 
-	metrics.APIRequestCount.With(prometheus.Labels{"provider": "openstack", "service": "nova"}).Inc()
+	onCall(novaService, "GetServer", start, err)
 	if err != nil {
 		if !IsNotFoundError(err) {
-			metrics.APIFailedRequestCount.With(prometheus.Labels{"provider": "openstack", "service": "nova"}).Inc()
+			onFailure(novaService)
 		}
-		return nil, err
+		return nil, withRequestID(err)
 	}
 	return server, nil
 }
 
 // ListServers lists all servers based on opts constraints.
-func (c *novaV2) ListServers(opts servers.ListOptsBuilder) ([]servers.Server, error) {
-	pages, err := servers.List(c.serviceClient, opts).AllPages()
+func (c *novaV2) ListServers(ctx context.Context, opts servers.ListOptsBuilder) ([]servers.Server, error) {
+	start := time.Now()
+	pages, err := servers.List(withContext(ctx, c.featureClient(FeatureServerTags)), opts).AllPages()
 
-	metrics.APIRequestCount.With(prometheus.Labels{"provider": "openstack", "service": "nova"}).Inc()
+	onCall(novaService, "ListServers", start, err)
 	if err != nil {
-		metrics.APIFailedRequestCount.With(prometheus.Labels{"provider": "openstack", "service": "nova"}).Inc()
-		return nil, err
+		onFailure(novaService)
+		return nil, withRequestID(err)
 	}
 	return servers.ExtractServers(pages)
 }
 
 // DeleteServer deletes a server with the supplied ID. If the server does not exist it returns nil.
-func (c *novaV2) DeleteServer(id string) error {
-	err := servers.Delete(c.serviceClient, id).ExtractErr()
+func (c *novaV2) DeleteServer(ctx context.Context, id string) error {
+	start := time.Now()
+	err := servers.Delete(withContext(ctx, c.serviceClient), id).ExtractErr()
+
+	onCall(novaService, "DeleteServer", start, err)
+	if err != nil && !IsNotFoundError(err) {
+		onFailure(novaService)
+		return withRequestID(err)
+	}
+	return nil
+}
+
+// UpdateServerMetadata creates or replaces the supplied metadata keys of a server.
+func (c *novaV2) UpdateServerMetadata(ctx context.Context, id string, opts servers.UpdateMetadataOptsBuilder) error {
+	start := time.Now()
+	_, err := servers.UpdateMetadata(withContext(ctx, c.serviceClient), id, opts).Extract()
+
+	onCall(novaService, "UpdateServerMetadata", start, err)
+	if err != nil {
+		onFailure(novaService)
+		return withRequestID(err)
+	}
+	return nil
+}
+
+// AddServerTags adds the tags to the server with the supplied ID. Existing tags of the server are kept.
+func (c *novaV2) AddServerTags(ctx context.Context, id string, serverTags []string) error {
+	serviceClient := withContext(ctx, c.featureClient(FeatureServerTags))
+	for _, tag := range serverTags {
+		start := time.Now()
+		err := tags.Add(serviceClient, id, tag).ExtractErr()
+
+		onCall(novaService, "AddServerTags", start, err)
+		if err != nil {
+			onFailure(novaService)
+			return withRequestID(err)
+		}
+	}
+	return nil
+}
+
+// CreateServerGroup creates a server group.
+func (c *novaV2) CreateServerGroup(ctx context.Context, opts servergroups.CreateOptsBuilder) (*servergroups.ServerGroup, error) {
+	start := time.Now()
+	group, err := servergroups.Create(withContext(ctx, withMicroversion(c.serviceClient, serverGroupMicroversion)), opts).Extract()
+
+	onCall(novaService, "CreateServerGroup", start, err)
+	if err != nil {
+		onFailure(novaService)
+		return nil, withRequestID(err)
+	}
+	return group, nil
+}
+
+// GetServerGroup fetches server group data from the supplied ID.
+func (c *novaV2) GetServerGroup(ctx context.Context, id string) (*servergroups.ServerGroup, error) {
+	start := time.Now()
+	group, err := servergroups.Get(withContext(ctx, c.serviceClient), id).Extract()
+
+	onCall(novaService, "GetServerGroup", start, err)
+	if err != nil {
+		if !IsNotFoundError(err) {
+			onFailure(novaService)
+		}
+		return nil, withRequestID(err)
+	}
+	return group, nil
+}
+
+// ListServerGroups lists all server groups.
+func (c *novaV2) ListServerGroups(ctx context.Context, opts servergroups.ListOptsBuilder) ([]servergroups.ServerGroup, error) {
+	start := time.Now()
+	pages, err := servergroups.List(withContext(ctx, c.serviceClient), opts).AllPages()
+
+	onCall(novaService, "ListServerGroups", start, err)
+	if err != nil {
+		onFailure(novaService)
+		return nil, withRequestID(err)
+	}
+	return servergroups.ExtractServerGroups(pages)
+}
+
+// DeleteServerGroup deletes a server group with the supplied ID. If the server group does not exist it returns nil.
+func (c *novaV2) DeleteServerGroup(ctx context.Context, id string) error {
+	start := time.Now()
+	err := servergroups.Delete(withContext(ctx, c.serviceClient), id).ExtractErr()
 
-	metrics.APIRequestCount.With(prometheus.Labels{"provider": "openstack", "service": "nova"}).Inc()
+	onCall(novaService, "DeleteServerGroup", start, err)
 	if err != nil && !IsNotFoundError(err) {
-		metrics.APIFailedRequestCount.With(prometheus.Labels{"provider": "openstack", "service": "nova"}).Inc()
-		return err
+		onFailure(novaService)
+		return withRequestID(err)
 	}
 	return nil
 }
 
 // ImageIDFromName resolves the given image name to a unique ID.
-func (c *novaV2) ImageIDFromName(name string) (string, error) {
-	id, err := images.IDFromName(c.serviceClient, name)
-	metrics.APIRequestCount.With(prometheus.Labels{"provider": "openstack", "service": "nova"}).Inc()
+func (c *novaV2) ImageIDFromName(ctx context.Context, name string) (string, error) {
+	// the image proxy API is only available up to microversion 2.35, hence the base service client is used
+	start := time.Now()
+	id, err := images.IDFromName(withContext(ctx, c.serviceClient), name)
+	onCall(novaService, "ImageIDFromName", start, err)
 	if err != nil {
 		if !IsNotFoundError(err) {
-			metrics.APIFailedRequestCount.With(prometheus.Labels{"provider": "openstack", "service": "nova"}).Inc()
+			onFailure(novaService)
 		}
-		return "", err
+		return "", withRequestID(err)
 	}
 
 	return id, nil
 }
 
 // FlavorIDFromName resolves the given flavor name to a unique ID.
-func (c *novaV2) FlavorIDFromName(name string) (string, error) {
-	id, err := flavors.IDFromName(c.serviceClient, name)
+func (c *novaV2) FlavorIDFromName(ctx context.Context, name string) (string, error) {
+	start := time.Now()
+	id, err := flavors.IDFromName(withContext(ctx, c.serviceClient), name)
 
-	metrics.APIRequestCount.With(prometheus.Labels{"provider": "openstack", "service": "nova"}).Inc()
+	onCall(novaService, "FlavorIDFromName", start, err)
 	if err != nil {
 		if !IsNotFoundError(err) {
-			metrics.APIFailedRequestCount.With(prometheus.Labels{"provider": "openstack", "service": "nova"}).Inc()
+			onFailure(novaService)
 		}
-		return "", err
+		return "", withRequestID(err)
 	}
 
 	return id, nil
@@ -0,0 +1,130 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack"
+	"github.com/gophercloud/gophercloud/openstack/identity/v3/tokens"
+)
+
+// defaultOIDCProtocol is the federation protocol used if the credentials do not specify one.
+const defaultOIDCProtocol = "openid"
+
+// oidcAuthenticator authenticates with an OIDC access token, e.g. a projected Kubernetes service account token, via
+// Keystone federation. The access token is exchanged for an unscoped token, which is in turn exchanged for a token
+// scoped to the project. This corresponds to the v3oidcaccesstoken authentication type of keystoneauth.
+type oidcAuthenticator struct {
+	identityProvider string
+	protocol         string
+	// accessToken is used if no accessTokenFile is set.
+	accessToken string
+	// accessTokenFile is read on every authentication, since projected tokens are rotated.
+	accessTokenFile string
+	scope           tokens.Scope
+}
+
+func newOIDCAuthenticator(credentials *credentials) *oidcAuthenticator {
+	protocol := credentials.Protocol
+	if protocol == "" {
+		protocol = defaultOIDCProtocol
+	}
+	return &oidcAuthenticator{
+		identityProvider: credentials.IdentityProvider,
+		protocol:         protocol,
+		accessToken:      credentials.AccessToken,
+		accessTokenFile:  credentials.AccessTokenFile,
+		scope: tokens.Scope{
+			ProjectID:   credentials.TenantID,
+			ProjectName: credentials.TenantName,
+			DomainID:    credentials.DomainID,
+			DomainName:  credentials.DomainName,
+		},
+	}
+}
+
+// authenticate authenticates the provider client and sets up its reauthentication, which exchanges the current access
+// token again once the scoped token expired.
+func (a *oidcAuthenticator) authenticate(provider *gophercloud.ProviderClient) error {
+	if err := a.exchangeToken(provider, true); err != nil {
+		return err
+	}
+
+	// reauthenticate through a throw-away copy of the provider client, so that a failing reauthentication is not retried
+	tac := *provider
+	tac.SetThrowaway(true)
+	tac.ReauthFunc = nil
+	provider.ReauthFunc = func() error {
+		if err := a.exchangeToken(&tac, false); err != nil {
+			return err
+		}
+		provider.CopyTokenFrom(&tac)
+		return nil
+	}
+	return nil
+}
+
+// exchangeToken obtains a scoped token for the provider client. The endpoint locator of the provider client is only
+// set on the initial authentication.
+func (a *oidcAuthenticator) exchangeToken(provider *gophercloud.ProviderClient, setEndpointLocator bool) error {
+	accessToken, err := a.readAccessToken()
+	if err != nil {
+		return err
+	}
+
+	// the expired token must not be sent along with the access token
+	if err := provider.SetTokenAndAuthResult(nil); err != nil {
+		return err
+	}
+	identity, err := openstack.NewIdentityV3(provider, gophercloud.EndpointOpts{})
+	if err != nil {
+		return err
+	}
+
+	url := identity.ServiceURL("OS-FEDERATION", "identity_providers", a.identityProvider, "protocols", a.protocol, "auth")
+	resp, err := identity.Post(url, nil, nil, &gophercloud.RequestOpts{
+		MoreHeaders: map[string]string{"Authorization": "Bearer " + accessToken},
+		OkCodes:     []int{http.StatusOK, http.StatusCreated},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to exchange access token at identity provider %q: %w", a.identityProvider, err)
+	}
+	unscopedToken := resp.Header.Get("X-Subject-Token")
+	if unscopedToken == "" {
+		return fmt.Errorf("identity provider %q did not return a token", a.identityProvider)
+	}
+
+	result := tokens.Create(identity, &tokens.AuthOptions{TokenID: unscopedToken, Scope: a.scope})
+	if err := provider.SetTokenAndAuthResult(result); err != nil {
+		return fmt.Errorf("failed to obtain project scoped token: %w", err)
+	}
+
+	if setEndpointLocator {
+		catalog, err := result.ExtractServiceCatalog()
+		if err != nil {
+			return err
+		}
+		provider.EndpointLocator = func(opts gophercloud.EndpointOpts) (string, error) {
+			return openstack.V3EndpointURL(catalog, opts)
+		}
+	}
+	return nil
+}
+
+func (a *oidcAuthenticator) readAccessToken() (string, error) {
+	if a.accessTokenFile == "" {
+		return a.accessToken, nil
+	}
+	data, err := os.ReadFile(a.accessTokenFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read access token: %w", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
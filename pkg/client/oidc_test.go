@@ -0,0 +1,124 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/gardener/machine-controller-manager-provider-openstack/pkg/apis/cloudprovider"
+)
+
+var _ = Describe("oidcAuthenticator", func() {
+	var (
+		keystone *fakeKeystone
+		provider *gophercloud.ProviderClient
+	)
+
+	BeforeEach(func() {
+		keystone = newFakeKeystone()
+		keystone.accessTokens.Insert("access-token")
+		DeferCleanup(keystone.close)
+
+		var err error
+		provider, err = openstack.NewClient(keystone.authURL())
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should exchange the access token for a project scoped token", func() {
+		authenticator := newOIDCAuthenticator(&credentials{
+			IdentityProvider: "kubernetes",
+			AccessToken:      "access-token",
+			TenantID:         "project-id",
+		})
+
+		Expect(authenticator.authenticate(provider)).To(Succeed())
+		Expect(provider.Token()).To(Equal("token-2"))
+		Expect(projectOf(provider)).To(Equal("project-id"))
+		// the unscoped token was issued for the default protocol
+		Expect(keystone.requests()).To(Equal([]string{"unscoped-kubernetes-openid-1"}))
+
+		endpoint, err := provider.EndpointLocator(gophercloud.EndpointOpts{Type: "compute", Availability: gophercloud.AvailabilityPublic})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(endpoint).To(Equal(keystone.server.URL + "/compute/v2.1/"))
+	})
+
+	It("should use the configured protocol", func() {
+		authenticator := newOIDCAuthenticator(&credentials{
+			IdentityProvider: "kubernetes",
+			Protocol:         "oidc",
+			AccessToken:      "access-token",
+		})
+
+		Expect(authenticator.authenticate(provider)).To(Succeed())
+		Expect(keystone.requests()).To(Equal([]string{"unscoped-kubernetes-oidc-1"}))
+	})
+
+	It("should fail if the access token is rejected", func() {
+		authenticator := newOIDCAuthenticator(&credentials{
+			IdentityProvider: "kubernetes",
+			AccessToken:      "expired",
+		})
+
+		err := authenticator.authenticate(provider)
+		Expect(err).To(MatchError(ContainSubstring(`failed to exchange access token at identity provider "kubernetes"`)))
+		Expect(IsUnauthenticated(err)).To(BeTrue())
+	})
+
+	Context("access token file", func() {
+		var tokenFile string
+
+		BeforeEach(func() {
+			tokenFile = filepath.Join(GinkgoT().TempDir(), "token")
+			Expect(os.WriteFile(tokenFile, []byte("access-token\n"), 0600)).To(Succeed())
+		})
+
+		It("should read the rotated access token on reauthentication", func() {
+			authenticator := newOIDCAuthenticator(&credentials{
+				IdentityProvider: "kubernetes",
+				AccessTokenFile:  tokenFile,
+			})
+			Expect(authenticator.authenticate(provider)).To(Succeed())
+			token := provider.Token()
+
+			// the projected token is rotated and the previous one is not accepted anymore
+			keystone.mutex.Lock()
+			keystone.accessTokens.Delete("access-token")
+			keystone.accessTokens.Insert("rotated-token")
+			keystone.mutex.Unlock()
+			Expect(os.WriteFile(tokenFile, []byte("rotated-token\n"), 0600)).To(Succeed())
+
+			Expect(provider.Reauthenticate(token)).To(Succeed())
+			Expect(provider.Token()).NotTo(Equal(token))
+			Expect(keystone.requests()).To(HaveLen(2))
+		})
+
+		It("should fail if the access token file cannot be read", func() {
+			authenticator := newOIDCAuthenticator(&credentials{
+				IdentityProvider: "kubernetes",
+				AccessTokenFile:  filepath.Join(GinkgoT().TempDir(), "missing"),
+			})
+
+			Expect(authenticator.authenticate(provider)).To(MatchError(ContainSubstring("failed to read access token")))
+			Expect(keystone.requests()).To(BeEmpty())
+		})
+	})
+
+	It("should authenticate the Factory with the access token of the secret", func() {
+		factory, err := newFactoryFromSecretData(map[string][]byte{
+			cloudprovider.OpenStackAuthURL:          []byte(keystone.authURL()),
+			cloudprovider.OpenStackIdentityProvider: []byte("kubernetes"),
+			cloudprovider.OpenStackAccessToken:      []byte("access-token"),
+			cloudprovider.OpenStackTenantID:         []byte("project-id"),
+		}, RateLimits{}, DefaultHTTPOptions())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(projectOf(factory.providerClient)).To(Equal("project-id"))
+	})
+})
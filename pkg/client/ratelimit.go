@@ -0,0 +1,168 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"math"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/identity/v3/tokens"
+	"github.com/spf13/pflag"
+	"golang.org/x/time/rate"
+)
+
+const (
+	readRequest  = "read"
+	writeRequest = "write"
+)
+
+// RateLimits configures the client-side rate limits for the OpenStack API. The limits apply to every service of a
+// project separately, and are shared by all clients targeting the same project. A QPS of zero disables the limit.
+type RateLimits struct {
+	// ReadQPS is the number of read requests per second which are sent to a service.
+	ReadQPS float64
+	// ReadBurst is the number of read requests which may be sent at once.
+	ReadBurst int
+	// WriteQPS is the number of write requests per second which are sent to a service.
+	WriteQPS float64
+	// WriteBurst is the number of write requests which may be sent at once.
+	WriteBurst int
+}
+
+// AddFlags adds the flags for the client-side rate limits to the given flag set.
+func (l *RateLimits) AddFlags(fs *pflag.FlagSet) {
+	fs.Float64Var(&l.ReadQPS, "openstack-api-read-qps", 0, "Maximum number of read requests per second sent to an OpenStack service of a project. Zero disables the limit.")
+	fs.IntVar(&l.ReadBurst, "openstack-api-read-burst", 0, "Maximum burst of read requests sent to an OpenStack service of a project. Defaults to the read QPS.")
+	fs.Float64Var(&l.WriteQPS, "openstack-api-write-qps", 0, "Maximum number of write requests per second sent to an OpenStack service of a project. Zero disables the limit.")
+	fs.IntVar(&l.WriteBurst, "openstack-api-write-burst", 0, "Maximum burst of write requests sent to an OpenStack service of a project. Defaults to the write QPS.")
+}
+
+func (l RateLimits) enabled() bool {
+	return l.ReadQPS > 0 || l.WriteQPS > 0
+}
+
+// projectRateLimiters holds the rate limiters of all projects, so that Factories targeting the same project share
+// their budget.
+var projectRateLimiters = &rateLimiterRegistry{
+	limiters: map[string]*projectRateLimiter{},
+}
+
+type rateLimiterRegistry struct {
+	mutex    sync.Mutex
+	limiters map[string]*projectRateLimiter
+}
+
+// get returns the rate limiter of the project the provider client is authenticated for.
+func (r *rateLimiterRegistry) get(authURL string, providerClient *gophercloud.ProviderClient, limits RateLimits) *projectRateLimiter {
+	key := authURL + "|" + projectOf(providerClient)
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	limiter, ok := r.limiters[key]
+	if !ok {
+		limiter = &projectRateLimiter{
+			limits:   limits,
+			limiters: map[string]*rate.Limiter{},
+		}
+		r.limiters[key] = limiter
+	}
+	return limiter
+}
+
+// projectOf returns the ID of the project the token of the provider client is scoped to.
+func projectOf(providerClient *gophercloud.ProviderClient) string {
+	result, ok := providerClient.GetAuthResult().(tokens.CreateResult)
+	if !ok {
+		return ""
+	}
+	project, err := result.ExtractProject()
+	if err != nil || project == nil {
+		return ""
+	}
+	return project.ID
+}
+
+// projectRateLimiter holds the token buckets of a project, one per service and kind of request.
+type projectRateLimiter struct {
+	limits RateLimits
+
+	mutex    sync.Mutex
+	limiters map[string]*rate.Limiter
+}
+
+// limiter returns the token bucket for the service and kind of request, or nil if the requests are not limited.
+func (l *projectRateLimiter) limiter(service, kind string) *rate.Limiter {
+	qps, burst := l.limits.ReadQPS, l.limits.ReadBurst
+	if kind == writeRequest {
+		qps, burst = l.limits.WriteQPS, l.limits.WriteBurst
+	}
+	if qps <= 0 {
+		return nil
+	}
+	if burst <= 0 {
+		burst = int(math.Ceil(qps))
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	key := service + "/" + kind
+	limiter, ok := l.limiters[key]
+	if !ok {
+		limiter = rate.NewLimiter(rate.Limit(qps), burst)
+		l.limiters[key] = limiter
+	}
+	return limiter
+}
+
+// rateLimitTransport is a http.RoundTripper, which delays requests until the token bucket of the service permits them.
+// Requests are passed through unchanged until a project rate limiter is set, i.e. while authenticating.
+type rateLimitTransport struct {
+	next    http.RoundTripper
+	catalog *serviceCatalog
+	limiter atomic.Pointer[projectRateLimiter]
+}
+
+func newRateLimitTransport(next http.RoundTripper, catalog *serviceCatalog) *rateLimitTransport {
+	return &rateLimitTransport{
+		next:    next,
+		catalog: catalog,
+	}
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	project := t.limiter.Load()
+	if project == nil {
+		return t.next.RoundTrip(req)
+	}
+
+	service, kind := t.catalog.serviceOf(req), requestKind(req)
+	limiter := project.limiter(service, kind)
+	if limiter == nil {
+		return t.next.RoundTrip(req)
+	}
+
+	start := time.Now()
+	err := limiter.Wait(req.Context())
+	onRateLimiterWait(service, kind, time.Since(start))
+	if err != nil {
+		return nil, err
+	}
+	return t.next.RoundTrip(req)
+}
+
+func requestKind(req *http.Request) string {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return readRequest
+	}
+	return writeRequest
+}
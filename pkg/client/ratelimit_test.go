@@ -0,0 +1,130 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"golang.org/x/time/rate"
+)
+
+var _ = Describe("Rate limits", func() {
+	Describe("#rateLimiterRegistry", func() {
+		var (
+			keystone *fakeKeystone
+			registry *rateLimiterRegistry
+			limits   = RateLimits{ReadQPS: 10, WriteQPS: 1}
+		)
+
+		BeforeEach(func() {
+			keystone = newFakeKeystone()
+			keystone.accepted.Insert("secret")
+			DeferCleanup(keystone.close)
+
+			registry = &rateLimiterRegistry{limiters: map[string]*projectRateLimiter{}}
+		})
+
+		It("should share the rate limiter of a project", func() {
+			limiter := registry.get(keystone.authURL(), keystone.providerClient("secret"), limits)
+			Expect(registry.get(keystone.authURL(), keystone.providerClient("secret"), limits)).To(BeIdenticalTo(limiter))
+			Expect(registry.limiters).To(HaveKey(keystone.authURL() + "|project-id"))
+		})
+
+		It("should separate the rate limiters of different projects", func() {
+			limiter := registry.get(keystone.authURL(), keystone.providerClient("secret"), limits)
+
+			keystone.mutex.Lock()
+			keystone.projectID = "other-project-id"
+			keystone.mutex.Unlock()
+			Expect(registry.get(keystone.authURL(), keystone.providerClient("secret"), limits)).NotTo(BeIdenticalTo(limiter))
+		})
+
+		It("should separate the rate limiters of projects with the same ID in different clouds", func() {
+			provider := keystone.providerClient("secret")
+			limiter := registry.get(keystone.authURL(), provider, limits)
+			Expect(registry.get("https://keystone.example.org/v3", provider, limits)).NotTo(BeIdenticalTo(limiter))
+		})
+	})
+
+	Describe("#projectRateLimiter", func() {
+		It("should limit read and write requests of a service separately", func() {
+			project := &projectRateLimiter{
+				limits:   RateLimits{ReadQPS: 10, ReadBurst: 20, WriteQPS: 1.5},
+				limiters: map[string]*rate.Limiter{},
+			}
+
+			read, write := project.limiter(novaService, readRequest), project.limiter(novaService, writeRequest)
+			Expect(read).NotTo(BeIdenticalTo(write))
+			Expect(read.Limit()).To(Equal(rate.Limit(10)))
+			Expect(read.Burst()).To(Equal(20))
+			Expect(write.Limit()).To(Equal(rate.Limit(1.5)))
+			Expect(write.Burst()).To(Equal(2))
+
+			Expect(project.limiter(novaService, readRequest)).To(BeIdenticalTo(read))
+			Expect(project.limiter(cinderService, readRequest)).NotTo(BeIdenticalTo(read))
+		})
+
+		It("should not limit requests without QPS", func() {
+			project := &projectRateLimiter{
+				limits:   RateLimits{ReadQPS: 10},
+				limiters: map[string]*rate.Limiter{},
+			}
+
+			Expect(project.limiter(novaService, writeRequest)).To(BeNil())
+		})
+	})
+
+	Describe("#rateLimitTransport", func() {
+		var (
+			server    *httptest.Server
+			transport *rateLimitTransport
+		)
+
+		BeforeEach(func() {
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+			DeferCleanup(server.Close)
+			transport = newRateLimitTransport(http.DefaultTransport, newServiceCatalog(map[string]string{"compute": server.URL + "/compute/v2.1"}))
+		})
+
+		send := func(method string, timeout time.Duration) error {
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+			req, err := http.NewRequestWithContext(ctx, method, server.URL+"/compute/v2.1/servers", nil)
+			Expect(err).NotTo(HaveOccurred())
+			resp, err := transport.RoundTrip(req)
+			if err != nil {
+				return err
+			}
+			return resp.Body.Close()
+		}
+
+		It("should not limit requests until the project rate limiter is set", func() {
+			for range 3 {
+				Expect(send(http.MethodGet, time.Second)).To(Succeed())
+			}
+		})
+
+		It("should delay read requests without limiting write requests", func() {
+			transport.limiter.Store(&projectRateLimiter{
+				limits:   RateLimits{ReadQPS: 0.1, ReadBurst: 1},
+				limiters: map[string]*rate.Limiter{},
+			})
+
+			Expect(send(http.MethodGet, time.Second)).To(Succeed())
+			// the next token is only available in ten seconds, which exceeds the deadline of the request
+			Expect(send(http.MethodGet, time.Second)).To(MatchError(ContainSubstring("would exceed context deadline")))
+			for range 3 {
+				Expect(send(http.MethodPost, time.Second)).To(Succeed())
+			}
+		})
+	})
+})
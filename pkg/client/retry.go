@@ -0,0 +1,160 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// maxRetries is the maximum number of times a request is retried after a transient failure.
+	maxRetries = 4
+	// initialRetryDelay is the delay before the first retry, which is doubled for every further retry.
+	initialRetryDelay = 500 * time.Millisecond
+	// maxRetryDelay caps the exponential growth of the retry delay.
+	maxRetryDelay = 8 * time.Second
+	// retryJitter is the maximum fraction by which a retry delay is randomly extended.
+	retryJitter = 0.2
+	// maxRetryAfter is the longest Retry-After delay which is waited for. Responses asking for a longer delay are returned
+	// to the caller without retrying.
+	maxRetryAfter = 30 * time.Second
+)
+
+// retryTransport is a http.RoundTripper, which retries idempotent requests failing with transient errors, i.e. server
+// errors, conflicts, rate limiting or reset connections. The delay between retries grows exponentially with jitter, a
+// Retry-After header sent by the server takes precedence.
+type retryTransport struct {
+	next    http.RoundTripper
+	catalog *serviceCatalog
+}
+
+func newRetryTransport(next http.RoundTripper, catalog *serviceCatalog) *retryTransport {
+	return &retryTransport{
+		next:    next,
+		catalog: catalog,
+	}
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !isIdempotent(req) {
+		return t.next.RoundTrip(req)
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := t.next.RoundTrip(req)
+		if attempt >= maxRetries || !isRetryable(resp, err) {
+			return resp, err
+		}
+
+		delay := retryDelay(attempt)
+		if resp != nil {
+			if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+				if retryAfter > maxRetryAfter {
+					return resp, err
+				}
+				delay = retryAfter
+			}
+		}
+
+		retry, rewindErr := rewindRequest(req)
+		if rewindErr != nil {
+			klog.V(3).Infof("not retrying %s request to %s: %v", req.Method, req.URL.Redacted(), rewindErr)
+			return resp, err
+		}
+		if resp != nil {
+			// drain the body so that the connection can be reused
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+
+		service := t.catalog.serviceOf(req)
+		onRetry(service)
+		klog.V(3).Infof("retrying %s request to %s service in %v (attempt %d/%d): %s", req.Method, service, delay, attempt+1, maxRetries, retryReason(resp, err))
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+		req = retry
+	}
+}
+
+// isIdempotent returns true for requests, which can safely be sent multiple times. OpenStack APIs use PUT only for
+// replacing resources or their attributes, therefore PUT requests are considered idempotent, unlike POST and PATCH.
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodDelete, http.MethodPut:
+		return true
+	}
+	return false
+}
+
+func isRetryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return errors.Is(err, syscall.ECONNRESET) || errors.Is(err, io.ErrUnexpectedEOF)
+	}
+	return resp.StatusCode >= http.StatusInternalServerError ||
+		resp.StatusCode == http.StatusConflict ||
+		resp.StatusCode == http.StatusTooManyRequests
+}
+
+func retryReason(resp *http.Response, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	return resp.Status
+}
+
+// retryDelay returns the exponentially growing delay before the given retry attempt.
+func retryDelay(attempt int) time.Duration {
+	delay := initialRetryDelay << attempt
+	if delay <= 0 || delay > maxRetryDelay {
+		delay = maxRetryDelay
+	}
+	return wait.Jitter(delay, retryJitter)
+}
+
+// parseRetryAfter parses the value of a Retry-After header, which is either a number of seconds or an HTTP date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+	return 0, false
+}
+
+// rewindRequest returns a copy of the request with a fresh body, which can be sent again.
+func rewindRequest(req *http.Request) (*http.Request, error) {
+	retry := req.Clone(req.Context())
+	if req.Body == nil || req.Body == http.NoBody {
+		return retry, nil
+	}
+	if req.GetBody == nil {
+		return nil, errors.New("request body cannot be rewound")
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	retry.Body = body
+	return retry, nil
+}
@@ -0,0 +1,199 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("retryTransport", func() {
+	var (
+		server *httptest.Server
+		client *http.Client
+
+		mutex sync.Mutex
+		// bodies are the request bodies of all attempts
+		bodies []string
+		// respond answers the attempt with the given index
+		respond func(w http.ResponseWriter, attempt int)
+	)
+
+	attempts := func() int {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return len(bodies)
+	}
+
+	// failFirst fails the first attempts with the status, asking for an immediate retry
+	failFirst := func(failures, status int) func(http.ResponseWriter, int) {
+		return func(w http.ResponseWriter, attempt int) {
+			if attempt < failures {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(status)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}
+	}
+
+	BeforeEach(func() {
+		bodies = nil
+		respond = failFirst(1, http.StatusServiceUnavailable)
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			mutex.Lock()
+			attempt := len(bodies)
+			bodies = append(bodies, string(body))
+			mutex.Unlock()
+			respond(w, attempt)
+		}))
+		DeferCleanup(server.Close)
+		client = &http.Client{Transport: newRetryTransport(http.DefaultTransport, newServiceCatalog(nil))}
+	})
+
+	send := func(ctx context.Context, method string, body io.Reader) (*http.Response, error) {
+		req, err := http.NewRequestWithContext(ctx, method, server.URL+"/servers", body)
+		Expect(err).NotTo(HaveOccurred())
+		resp, err := client.Do(req)
+		if resp != nil {
+			DeferCleanup(resp.Body.Close)
+		}
+		return resp, err
+	}
+
+	DescribeTable("should only retry idempotent requests",
+		func(method string, expectedAttempts int) {
+			resp, err := send(context.Background(), method, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(attempts()).To(Equal(expectedAttempts))
+			if expectedAttempts > 1 {
+				Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			} else {
+				Expect(resp.StatusCode).To(Equal(http.StatusServiceUnavailable))
+			}
+		},
+		Entry("GET", http.MethodGet, 2),
+		Entry("HEAD", http.MethodHead, 2),
+		Entry("OPTIONS", http.MethodOptions, 2),
+		Entry("DELETE", http.MethodDelete, 2),
+		Entry("PUT", http.MethodPut, 2),
+		Entry("POST", http.MethodPost, 1),
+		Entry("PATCH", http.MethodPatch, 1),
+	)
+
+	DescribeTable("should only retry transient failures",
+		func(status int, expectedAttempts int) {
+			respond = failFirst(1, status)
+
+			_, err := send(context.Background(), http.MethodGet, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(attempts()).To(Equal(expectedAttempts))
+		},
+		Entry("internal server error", http.StatusInternalServerError, 2),
+		Entry("bad gateway", http.StatusBadGateway, 2),
+		Entry("service unavailable", http.StatusServiceUnavailable, 2),
+		Entry("conflict", http.StatusConflict, 2),
+		Entry("too many requests", http.StatusTooManyRequests, 2),
+		Entry("bad request", http.StatusBadRequest, 1),
+		Entry("unauthorized", http.StatusUnauthorized, 1),
+		Entry("not found", http.StatusNotFound, 1),
+	)
+
+	It("should give up after the maximum number of retries", func() {
+		respond = failFirst(maxRetries+1, http.StatusServiceUnavailable)
+
+		resp, err := send(context.Background(), http.MethodGet, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.StatusCode).To(Equal(http.StatusServiceUnavailable))
+		Expect(attempts()).To(Equal(maxRetries + 1))
+	})
+
+	It("should not wait for a Retry-After delay exceeding the maximum", func() {
+		respond = func(w http.ResponseWriter, _ int) {
+			w.Header().Set("Retry-After", fmt.Sprint(int((maxRetryAfter + time.Second).Seconds())))
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+
+		start := time.Now()
+		resp, err := send(context.Background(), http.MethodGet, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.StatusCode).To(Equal(http.StatusTooManyRequests))
+		Expect(attempts()).To(Equal(1))
+		Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+	})
+
+	It("should send the request body again", func() {
+		_, err := send(context.Background(), http.MethodPut, strings.NewReader(`{"metadata": {"foo": "bar"}}`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(bodies).To(Equal([]string{`{"metadata": {"foo": "bar"}}`, `{"metadata": {"foo": "bar"}}`}))
+	})
+
+	It("should not retry requests whose body cannot be rewound", func() {
+		_, err := send(context.Background(), http.MethodPut, io.NopCloser(strings.NewReader(`{}`)))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(attempts()).To(Equal(1))
+	})
+
+	It("should stop retrying once the context is canceled", func() {
+		respond = func(w http.ResponseWriter, _ int) {
+			w.Header().Set("Retry-After", "10")
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		DeferCleanup(cancel)
+		go func() {
+			defer GinkgoRecover()
+			Eventually(attempts).Should(Equal(1))
+			cancel()
+		}()
+
+		start := time.Now()
+		_, err := send(ctx, http.MethodGet, nil)
+		Expect(err).To(MatchError(context.Canceled))
+		Expect(attempts()).To(Equal(1))
+		Expect(time.Since(start)).To(BeNumerically("<", 5*time.Second))
+	})
+
+	Describe("#isRetryable", func() {
+		It("should retry reset connections", func() {
+			Expect(isRetryable(nil, fmt.Errorf("read: %w", syscall.ECONNRESET))).To(BeTrue())
+			Expect(isRetryable(nil, io.ErrUnexpectedEOF)).To(BeTrue())
+			Expect(isRetryable(nil, syscall.ECONNREFUSED)).To(BeFalse())
+		})
+	})
+
+	Describe("#retryDelay", func() {
+		It("should grow exponentially up to the maximum delay", func() {
+			Expect(retryDelay(0)).To(BeNumerically("~", initialRetryDelay, time.Duration(float64(initialRetryDelay)*retryJitter)))
+			Expect(retryDelay(1)).To(BeNumerically(">=", 2*initialRetryDelay))
+			Expect(retryDelay(10)).To(BeNumerically("~", maxRetryDelay, time.Duration(float64(maxRetryDelay)*retryJitter)))
+			Expect(retryDelay(100)).To(BeNumerically(">=", maxRetryDelay))
+		})
+	})
+
+	Describe("#parseRetryAfter", func() {
+		It("should parse seconds and HTTP dates", func() {
+			delay, ok := parseRetryAfter("3")
+			Expect(ok).To(BeTrue())
+			Expect(delay).To(Equal(3 * time.Second))
+			delay, ok = parseRetryAfter(time.Now().Add(time.Minute).UTC().Format(http.TimeFormat))
+			Expect(ok).To(BeTrue())
+			Expect(delay).To(BeNumerically("~", time.Minute, 2*time.Second))
+			_, ok = parseRetryAfter("soon")
+			Expect(ok).To(BeFalse())
+		})
+	})
+})
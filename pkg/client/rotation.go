@@ -0,0 +1,119 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// primaryCredential names the credentials given by the regular keys of the secret.
+	primaryCredential = "primary"
+	// nextCredential names the credentials given by the keys with the ".next" suffix, which replace the primary ones
+	// during a rotation.
+	nextCredential = "next"
+)
+
+var (
+	// credentialInUse reports the credentials the clients of a project are authenticated with, partitioned by provider,
+	// project and credential.
+	credentialInUse = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "mcm",
+		Subsystem: "cloud_api",
+		Name:      "credential_in_use",
+		Help:      "Whether the credential is used to authenticate at the Cloud Service API, partitioned by provider, project, and credential.",
+	}, []string{"provider", "project", "credential"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(credentialInUse)
+}
+
+// namedAuthOptions are the options to authenticate with one of the credentials of the secret.
+type namedAuthOptions struct {
+	name        string
+	authOptions gophercloud.AuthOptions
+}
+
+// rotatingAuthenticator authenticates with the primary credentials and falls back to the next credentials once the
+// primary ones are rejected, e.g. because they were revoked during a rotation. Once the next credentials are used, the
+// authenticator does not return to the primary ones, since they are about to be removed from the secret.
+type rotatingAuthenticator struct {
+	credentials []namedAuthOptions
+	// active is the index of the credentials used for the last successful authentication. It is only accessed during
+	// authentications, which are serialized by the provider client.
+	active int
+}
+
+func newRotatingAuthenticator(credentials ...namedAuthOptions) *rotatingAuthenticator {
+	return &rotatingAuthenticator{
+		credentials: credentials,
+	}
+}
+
+// authenticate authenticates the provider client and sets up its reauthentication, which falls back to the next
+// credentials as well.
+func (a *rotatingAuthenticator) authenticate(provider *gophercloud.ProviderClient) error {
+	if err := a.authenticateWithActive(provider); err != nil {
+		return err
+	}
+
+	// reauthenticate through a throw-away copy of the provider client, so that a failing reauthentication is not retried
+	tac := *provider
+	tac.SetThrowaway(true)
+	tac.ReauthFunc = nil
+	provider.ReauthFunc = func() error {
+		// the expired token must not be sent along with the credentials
+		if err := tac.SetTokenAndAuthResult(nil); err != nil {
+			return err
+		}
+		if err := a.authenticateWithActive(&tac); err != nil {
+			return err
+		}
+		provider.CopyTokenFrom(&tac)
+		return nil
+	}
+	return nil
+}
+
+// authenticateWithActive authenticates with the active credentials, or the following ones if they are rejected.
+func (a *rotatingAuthenticator) authenticateWithActive(provider *gophercloud.ProviderClient) error {
+	var err error
+	for i := a.active; i < len(a.credentials); i++ {
+		creds := a.credentials[i]
+		// the reauthentication is set up by the authenticator instead
+		creds.authOptions.AllowReauth = false
+		if err = openstack.Authenticate(provider, creds.authOptions); err == nil {
+			if i != a.active {
+				klog.Infof("authenticated with %s OpenStack credentials, since the %s credentials were rejected", creds.name, a.credentials[a.active].name)
+			}
+			a.active = i
+			a.report(projectOf(provider))
+			return nil
+		}
+		if !IsUnauthenticated(err) {
+			return err
+		}
+		if i+1 < len(a.credentials) {
+			klog.Warningf("%s OpenStack credentials were rejected, falling back to %s credentials: %v", creds.name, a.credentials[i+1].name, err)
+		}
+	}
+	return err
+}
+
+// report records which of the credentials is used for the project.
+func (a *rotatingAuthenticator) report(project string) {
+	for i, creds := range a.credentials {
+		value := 0.0
+		if i == a.active {
+			value = 1
+		}
+		credentialInUse.With(prometheus.Labels{"provider": "openstack", "project": project, "credential": creds.name}).Set(value)
+	}
+}
@@ -0,0 +1,126 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/gardener/machine-controller-manager-provider-openstack/pkg/apis/cloudprovider"
+)
+
+var _ = Describe("rotatingAuthenticator", func() {
+	var (
+		keystone      *fakeKeystone
+		provider      *gophercloud.ProviderClient
+		authenticator *rotatingAuthenticator
+	)
+
+	authOptions := func(name, secret string) namedAuthOptions {
+		return namedAuthOptions{name: name, authOptions: gophercloud.AuthOptions{
+			IdentityEndpoint:            keystone.authURL(),
+			ApplicationCredentialID:     "app-id",
+			ApplicationCredentialSecret: secret,
+		}}
+	}
+
+	inUse := func(credential string) float64 {
+		return metricValue("mcm_cloud_api_credential_in_use", prometheus.Labels{"provider": "openstack", "project": keystone.projectID, "credential": credential})
+	}
+
+	BeforeEach(func() {
+		keystone = newFakeKeystone()
+		keystone.projectID = "rotation-project-id"
+		DeferCleanup(keystone.close)
+
+		var err error
+		provider, err = openstack.NewClient(keystone.authURL())
+		Expect(err).NotTo(HaveOccurred())
+
+		authenticator = newRotatingAuthenticator(
+			authOptions(primaryCredential, "primary-secret"),
+			authOptions(nextCredential, "next-secret"),
+		)
+	})
+
+	It("should authenticate with the primary credentials", func() {
+		keystone.accepted.Insert("primary-secret", "next-secret")
+
+		Expect(authenticator.authenticate(provider)).To(Succeed())
+		Expect(keystone.requests()).To(Equal([]string{"primary-secret"}))
+		Expect(inUse(primaryCredential)).To(Equal(1.0))
+		Expect(inUse(nextCredential)).To(BeZero())
+	})
+
+	It("should fall back to the next credentials if the primary ones are rejected", func() {
+		keystone.accepted.Insert("next-secret")
+
+		Expect(authenticator.authenticate(provider)).To(Succeed())
+		Expect(provider.Token()).To(Equal("token-1"))
+		Expect(keystone.requests()).To(Equal([]string{"primary-secret", "next-secret"}))
+		Expect(inUse(primaryCredential)).To(BeZero())
+		Expect(inUse(nextCredential)).To(Equal(1.0))
+	})
+
+	It("should fall back to the next credentials on reauthentication and stay with them", func() {
+		keystone.accepted.Insert("primary-secret", "next-secret")
+		Expect(authenticator.authenticate(provider)).To(Succeed())
+
+		// the primary application credential is revoked during the rotation
+		keystone.mutex.Lock()
+		keystone.accepted.Delete("primary-secret")
+		keystone.mutex.Unlock()
+		Expect(provider.Reauthenticate(provider.Token())).To(Succeed())
+		Expect(inUse(nextCredential)).To(Equal(1.0))
+
+		Expect(provider.Reauthenticate(provider.Token())).To(Succeed())
+		Expect(keystone.requests()).To(Equal([]string{"primary-secret", "primary-secret", "next-secret", "next-secret"}))
+		Expect(inUse(primaryCredential)).To(BeZero())
+	})
+
+	It("should fail if all credentials are rejected", func() {
+		err := authenticator.authenticate(provider)
+		Expect(IsUnauthenticated(err)).To(BeTrue())
+		Expect(keystone.requests()).To(Equal([]string{"primary-secret", "next-secret"}))
+	})
+
+	It("should not fall back to the next credentials for other errors", func() {
+		var requests atomic.Int32
+		unavailable := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			requests.Add(1)
+			http.Error(w, `{"error": {"code": 503, "message": "Service Unavailable"}}`, http.StatusServiceUnavailable)
+		}))
+		DeferCleanup(unavailable.Close)
+
+		var err error
+		provider, err = openstack.NewClient(unavailable.URL + "/v3")
+		Expect(err).NotTo(HaveOccurred())
+
+		err = authenticator.authenticate(provider)
+		Expect(err).To(HaveOccurred())
+		Expect(IsUnauthenticated(err)).To(BeFalse())
+		Expect(requests.Load()).To(Equal(int32(1)))
+	})
+
+	It("should authenticate the Factory with the next application credential of the secret", func() {
+		keystone.accepted.Insert("next-secret")
+		data := keystone.applicationCredentialSecret("primary-secret")
+		data[cloudprovider.OpenStackNextApplicationCredentialID] = []byte("next-app-id")
+		data[cloudprovider.OpenStackNextApplicationCredentialSecret] = []byte("next-secret")
+
+		factory, err := newFactoryFromSecretData(data, RateLimits{}, DefaultHTTPOptions())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(projectOf(factory.providerClient)).To(Equal(keystone.projectID))
+		Expect(keystone.requests()).To(Equal([]string{"primary-secret", "next-secret"}))
+		Expect(inUse(nextCredential)).To(Equal(1.0))
+	})
+})
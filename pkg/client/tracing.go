@@ -0,0 +1,62 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"fmt"
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/gardener/machine-controller-manager-provider-openstack/pkg/tracing"
+)
+
+// tracingTransport is a http.RoundTripper, which records a client span for every request sent to the OpenStack API.
+// The spans are children of the span in the context of the request.
+type tracingTransport struct {
+	next    http.RoundTripper
+	catalog *serviceCatalog
+}
+
+func newTracingTransport(next http.RoundTripper, catalog *serviceCatalog) *tracingTransport {
+	return &tracingTransport{
+		next:    next,
+		catalog: catalog,
+	}
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (t *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	service := t.catalog.serviceOf(req)
+	ctx, span := tracing.Start(req.Context(), fmt.Sprintf("%s %s", req.Method, service),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.HTTPRequestMethodKey.String(req.Method),
+			semconv.URLFull(req.URL.Redacted()),
+			semconv.ServerAddress(req.URL.Hostname()),
+			attribute.String("openstack.service", service),
+		),
+	)
+	defer span.End()
+
+	resp, err := t.next.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return resp, err
+	}
+
+	span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
+	if id := requestIDFromHeader(resp.Header); id != "" {
+		span.SetAttributes(attribute.String("openstack.request_id", id))
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+	}
+	return resp, nil
+}
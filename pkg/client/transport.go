@@ -0,0 +1,106 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/spf13/pflag"
+	"k8s.io/klog/v2"
+)
+
+// HTTPOptions configures the HTTP client used for the requests to the OpenStack API. A timeout of zero disables it.
+type HTTPOptions struct {
+	// DialTimeout is the maximum time to establish a TCP connection.
+	DialTimeout time.Duration
+	// TLSHandshakeTimeout is the maximum time to perform the TLS handshake.
+	TLSHandshakeTimeout time.Duration
+	// ResponseHeaderTimeout is the maximum time to wait for the response headers after the request was sent.
+	ResponseHeaderTimeout time.Duration
+	// RequestTimeout is the maximum time of a request including its retries and reading the response body.
+	RequestTimeout time.Duration
+	// MaxIdleConns is the maximum number of idle connections across all hosts. Zero means no limit.
+	MaxIdleConns int
+	// MaxIdleConnsPerHost is the maximum number of idle connections kept per host.
+	MaxIdleConnsPerHost int
+	// IdleConnTimeout is the maximum time an idle connection is kept open.
+	IdleConnTimeout time.Duration
+}
+
+// DefaultHTTPOptions returns the HTTPOptions used if no flags are given.
+func DefaultHTTPOptions() HTTPOptions {
+	return HTTPOptions{
+		DialTimeout:           30 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: time.Minute,
+		RequestTimeout:        5 * time.Minute,
+		MaxIdleConns:          100,
+		MaxIdleConnsPerHost:   10,
+		IdleConnTimeout:       90 * time.Second,
+	}
+}
+
+// AddFlags adds the flags for the HTTP client to the given flag set.
+func (o *HTTPOptions) AddFlags(fs *pflag.FlagSet) {
+	defaults := DefaultHTTPOptions()
+	fs.DurationVar(&o.DialTimeout, "openstack-api-dial-timeout", defaults.DialTimeout, "Timeout for establishing connections to the OpenStack API. Zero disables the timeout.")
+	fs.DurationVar(&o.TLSHandshakeTimeout, "openstack-api-tls-handshake-timeout", defaults.TLSHandshakeTimeout, "Timeout for the TLS handshake with the OpenStack API. Zero disables the timeout.")
+	fs.DurationVar(&o.ResponseHeaderTimeout, "openstack-api-response-header-timeout", defaults.ResponseHeaderTimeout, "Timeout for waiting on the response headers of the OpenStack API. Zero disables the timeout.")
+	fs.DurationVar(&o.RequestTimeout, "openstack-api-request-timeout", defaults.RequestTimeout, "Overall timeout of a request to the OpenStack API including its retries. Zero disables the timeout.")
+	fs.IntVar(&o.MaxIdleConns, "openstack-api-max-idle-conns", defaults.MaxIdleConns, "Maximum number of idle connections to the OpenStack API across all hosts. Zero means no limit.")
+	fs.IntVar(&o.MaxIdleConnsPerHost, "openstack-api-max-idle-conns-per-host", defaults.MaxIdleConnsPerHost, "Maximum number of idle connections kept per OpenStack API host.")
+	fs.DurationVar(&o.IdleConnTimeout, "openstack-api-idle-conn-timeout", defaults.IdleConnTimeout, "Time after which idle connections to the OpenStack API are closed. Zero keeps them open.")
+}
+
+// newHTTPTransport returns the transport for the requests to the OpenStack API. Requests are sent through the proxy
+// configured in the credentials, or the one of the environment otherwise.
+func newHTTPTransport(credentials *credentials, config *tls.Config, opts HTTPOptions) (*http.Transport, error) {
+	proxy := http.ProxyFromEnvironment
+	if credentials.ProxyURL != "" {
+		proxyURL, err := url.Parse(credentials.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse proxy URL: %w", err)
+		}
+		proxy = http.ProxyURL(proxyURL)
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   opts.DialTimeout,
+		KeepAlive: 30 * time.Second,
+	}
+	return &http.Transport{
+		Proxy:                 proxy,
+		DialContext:           dialer.DialContext,
+		TLSClientConfig:       config,
+		TLSHandshakeTimeout:   opts.TLSHandshakeTimeout,
+		ResponseHeaderTimeout: opts.ResponseHeaderTimeout,
+		MaxIdleConns:          opts.MaxIdleConns,
+		MaxIdleConnsPerHost:   opts.MaxIdleConnsPerHost,
+		IdleConnTimeout:       opts.IdleConnTimeout,
+		ForceAttemptHTTP2:     true,
+	}, nil
+}
+
+// newRootCAs returns the pool of the certificate authorities trusted by the client. If caCertAppend is set, the CA
+// certificate is trusted in addition to the ones of the system, otherwise it replaces them.
+func newRootCAs(caCert []byte, caCertAppend bool) *x509.CertPool {
+	pool := x509.NewCertPool()
+	if caCertAppend {
+		systemPool, err := x509.SystemCertPool()
+		if err != nil {
+			klog.Warningf("failed to load system certificate pool, trusting the CA certificate only: %v", err)
+		} else {
+			pool = systemPool
+		}
+	}
+	pool.AppendCertsFromPEM(caCert)
+	return pool
+}
@@ -0,0 +1,99 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Transport", func() {
+	Describe("#newRootCAs", func() {
+		var (
+			server *httptest.Server
+			caCert []byte
+		)
+
+		BeforeEach(func() {
+			server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+			DeferCleanup(server.Close)
+			caCert = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+		})
+
+		get := func(pool *x509.CertPool) error {
+			client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}}}
+			resp, err := client.Get(server.URL)
+			if err != nil {
+				return err
+			}
+			return resp.Body.Close()
+		}
+
+		It("should only trust the CA certificate if it is not appended", func() {
+			pool := newRootCAs(caCert, false)
+
+			expected := x509.NewCertPool()
+			expected.AddCert(server.Certificate())
+			Expect(pool.Equal(expected)).To(BeTrue())
+			Expect(get(pool)).To(Succeed())
+		})
+
+		It("should trust the CA certificate in addition to the system certificate authorities if it is appended", func() {
+			systemPool, err := x509.SystemCertPool()
+			if err != nil {
+				Skip("system certificate pool is not available: " + err.Error())
+			}
+
+			pool := newRootCAs(caCert, true)
+
+			systemPool.AddCert(server.Certificate())
+			Expect(pool.Equal(systemPool)).To(BeTrue())
+			Expect(get(pool)).To(Succeed())
+		})
+
+		It("should not trust the server without its CA certificate", func() {
+			Expect(get(x509.NewCertPool())).To(MatchError(ContainSubstring("certificate")))
+		})
+	})
+
+	Describe("#newHTTPTransport", func() {
+		It("should apply the HTTP options", func() {
+			opts := DefaultHTTPOptions()
+			opts.MaxIdleConnsPerHost = 3
+
+			transport, err := newHTTPTransport(&credentials{}, nil, opts)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(transport.TLSHandshakeTimeout).To(Equal(opts.TLSHandshakeTimeout))
+			Expect(transport.ResponseHeaderTimeout).To(Equal(opts.ResponseHeaderTimeout))
+			Expect(transport.MaxIdleConns).To(Equal(opts.MaxIdleConns))
+			Expect(transport.MaxIdleConnsPerHost).To(Equal(3))
+			Expect(transport.IdleConnTimeout).To(Equal(opts.IdleConnTimeout))
+		})
+
+		It("should send the requests through the proxy of the credentials", func() {
+			transport, err := newHTTPTransport(&credentials{ProxyURL: "http://proxy.example.org:3128"}, nil, DefaultHTTPOptions())
+			Expect(err).NotTo(HaveOccurred())
+
+			req, err := http.NewRequest(http.MethodGet, "https://keystone.example.org/v3", nil)
+			Expect(err).NotTo(HaveOccurred())
+			proxy, err := transport.Proxy(req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(proxy.String()).To(Equal("http://proxy.example.org:3128"))
+		})
+
+		It("should fail for an invalid proxy URL", func() {
+			_, err := newHTTPTransport(&credentials{ProxyURL: "http://proxy.example.org:port"}, nil, DefaultHTTPOptions())
+			Expect(err).To(MatchError(ContainSubstring("failed to parse proxy URL")))
+		})
+	})
+})
@@ -5,65 +5,154 @@
 package client
 
 import (
+	"context"
+	"fmt"
+
 	"github.com/gophercloud/gophercloud/openstack/blockstorage/v3/volumes"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/servergroups"
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+	"github.com/gophercloud/gophercloud/openstack/imageservice/v2/images"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/floatingips"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/ports"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/subnets"
 )
 
+// Feature is an optional API feature, which is only available from a minimum API microversion on.
+type Feature string
+
+const (
+	// FeatureServerTags allows tagging servers and filtering servers by tags.
+	FeatureServerTags Feature = "ServerTags"
+	// FeatureServerCreateTags allows tagging servers when they are created.
+	FeatureServerCreateTags Feature = "ServerCreateTags"
+	// FeatureServerDescription allows setting the description of a server.
+	FeatureServerDescription Feature = "ServerDescription"
+	// FeatureServerHostname allows setting the hostname of a server.
+	FeatureServerHostname Feature = "ServerHostname"
+	// FeatureMultiattach allows creating volumes which can be attached to multiple servers and attaching them.
+	FeatureMultiattach Feature = "Multiattach"
+)
+
+// MultiattachCreateOptsExt marks a server create request, whose block device mapping contains volumes which can be
+// attached to multiple servers, so that it is sent with the microversion required to attach them.
+type MultiattachCreateOptsExt struct {
+	servers.CreateOptsBuilder
+}
+
+// ServerDetailsCreateOptsExt adds the description and the hostname of a server to a server create request. Empty fields
+// are omitted, set fields require FeatureServerDescription and FeatureServerHostname respectively.
+type ServerDetailsCreateOptsExt struct {
+	servers.CreateOptsBuilder
+	Description string
+	Hostname    string
+}
+
+// ToServerCreateMap adds the description and the hostname to the create request of the wrapped builder.
+func (opts ServerDetailsCreateOptsExt) ToServerCreateMap() (map[string]interface{}, error) {
+	body, err := opts.CreateOptsBuilder.ToServerCreateMap()
+	if err != nil {
+		return nil, err
+	}
+	server, ok := body["server"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("server create request has no server")
+	}
+	if opts.Description != "" {
+		server["description"] = opts.Description
+	}
+	if opts.Hostname != "" {
+		server["hostname"] = opts.Hostname
+	}
+	return body, nil
+}
+
 // Compute is an interface for communication with Nova service.
 type Compute interface {
 	// CreateServer creates a server.
-	CreateServer(opts servers.CreateOptsBuilder) (*servers.Server, error)
+	CreateServer(ctx context.Context, opts servers.CreateOptsBuilder) (*servers.Server, error)
 	// BootFromVolume creates a server from a block device mapping.
-	BootFromVolume(opts servers.CreateOptsBuilder) (*servers.Server, error)
+	BootFromVolume(ctx context.Context, opts servers.CreateOptsBuilder) (*servers.Server, error)
 	// GetServer fetches server data from the supplied ID.
-	GetServer(id string) (*servers.Server, error)
+	GetServer(ctx context.Context, id string) (*servers.Server, error)
 	// ListServers lists all servers based on opts constraints.
-	ListServers(opts servers.ListOptsBuilder) ([]servers.Server, error)
+	ListServers(ctx context.Context, opts servers.ListOptsBuilder) ([]servers.Server, error)
 	// DeleteServer deletes a server with the supplied ID. If the server does not exist it returns nil.
-	DeleteServer(id string) error
+	DeleteServer(ctx context.Context, id string) error
+	// UpdateServerMetadata creates or replaces the supplied metadata keys of a server.
+	UpdateServerMetadata(ctx context.Context, id string, opts servers.UpdateMetadataOptsBuilder) error
+	// Supports reports whether the compute API supports the optional feature.
+	Supports(feature Feature) bool
+	// AddServerTags adds the tags to the server with the supplied ID. Existing tags of the server are kept.
+	AddServerTags(ctx context.Context, id string, tags []string) error
+
+	// CreateServerGroup creates a server group.
+	CreateServerGroup(ctx context.Context, opts servergroups.CreateOptsBuilder) (*servergroups.ServerGroup, error)
+	// GetServerGroup fetches server group data from the supplied ID.
+	GetServerGroup(ctx context.Context, id string) (*servergroups.ServerGroup, error)
+	// ListServerGroups lists all server groups.
+	ListServerGroups(ctx context.Context, opts servergroups.ListOptsBuilder) ([]servergroups.ServerGroup, error)
+	// DeleteServerGroup deletes a server group with the supplied ID. If the server group does not exist it returns nil.
+	DeleteServerGroup(ctx context.Context, id string) error
 
 	// FlavorIDFromName resolves the given flavor name to a unique ID.
-	FlavorIDFromName(name string) (string, error)
+	FlavorIDFromName(ctx context.Context, name string) (string, error)
 	// ImageIDFromName resolves the given image name to a unique ID.
-	ImageIDFromName(name string) (string, error)
+	ImageIDFromName(ctx context.Context, name string) (string, error)
 }
 
 // Network is an interface for communication with Neutron service.
 type Network interface {
 	// GetSubnet fetches the subnet data from the supplied ID.
-	GetSubnet(id string) (*subnets.Subnet, error)
+	GetSubnet(ctx context.Context, id string) (*subnets.Subnet, error)
 
 	// CreatePort creates a Neutron port.
-	CreatePort(opts ports.CreateOptsBuilder) (*ports.Port, error)
+	CreatePort(ctx context.Context, opts ports.CreateOptsBuilder) (*ports.Port, error)
 	// ListPorts lists all ports.
-	ListPorts(opts ports.ListOptsBuilder) ([]ports.Port, error)
+	ListPorts(ctx context.Context, opts ports.ListOptsBuilder) ([]ports.Port, error)
 	// UpdatePort updates the port from the supplied ID.
-	UpdatePort(id string, opts ports.UpdateOptsBuilder) error
+	UpdatePort(ctx context.Context, id string, opts ports.UpdateOptsBuilder) error
 	// DeletePort deletes the port from the supplied ID.
-	DeletePort(id string) error
+	DeletePort(ctx context.Context, id string) error
 
 	// NetworkIDFromName resolves the given network name to a unique ID.
-	NetworkIDFromName(name string) (string, error)
+	NetworkIDFromName(ctx context.Context, name string) (string, error)
 	// GroupIDFromName resolves the given security group name to a unique ID.
-	GroupIDFromName(name string) (string, error)
+	GroupIDFromName(ctx context.Context, name string) (string, error)
 	// PortIDFromName resolves the given port name to a unique ID.
-	PortIDFromName(name string) (string, error)
+	PortIDFromName(ctx context.Context, name string) (string, error)
 	// TagPort tags a port with the specified labels.
-	TagPort(id string, tags []string) error
+	TagPort(ctx context.Context, id string, tags []string) error
+
+	// CreateFloatingIP creates a floating IP.
+	CreateFloatingIP(ctx context.Context, opts floatingips.CreateOptsBuilder) (*floatingips.FloatingIP, error)
+	// ListFloatingIPs lists all floating IPs.
+	ListFloatingIPs(ctx context.Context, opts floatingips.ListOptsBuilder) ([]floatingips.FloatingIP, error)
+	// UpdateFloatingIP updates the floating IP from the supplied ID.
+	UpdateFloatingIP(ctx context.Context, id string, opts floatingips.UpdateOptsBuilder) error
+	// DeleteFloatingIP deletes the floating IP from the supplied ID.
+	DeleteFloatingIP(ctx context.Context, id string) error
+	// TagFloatingIP tags a floating IP with the specified labels.
+	TagFloatingIP(ctx context.Context, id string, tags []string) error
 }
 
 // Storage is an interface for communication with Cinder service.
 type Storage interface {
 	// CreateVolume creates a Cinder volume.
-	CreateVolume(opts volumes.CreateOptsBuilder) (*volumes.Volume, error)
+	CreateVolume(ctx context.Context, opts volumes.CreateOptsBuilder) (*volumes.Volume, error)
 	// GetVolume retrieves information about a volume.
-	GetVolume(id string) (*volumes.Volume, error)
+	GetVolume(ctx context.Context, id string) (*volumes.Volume, error)
 	// DeleteVolume deletes a volume
-	DeleteVolume(id string) error
+	DeleteVolume(ctx context.Context, id string) error
 	// VolumeIDFromName resolves the given volume name to a unique ID.
-	VolumeIDFromName(name string) (string, error)
+	VolumeIDFromName(ctx context.Context, name string) (string, error)
 	// ListVolumes lists all volumes
-	ListVolumes(opts volumes.ListOptsBuilder) ([]volumes.Volume, error)
+	ListVolumes(ctx context.Context, opts volumes.ListOptsBuilder) ([]volumes.Volume, error)
+	// Supports reports whether the block storage API supports the optional feature.
+	Supports(feature Feature) bool
+}
+
+// Image is an interface for communication with Glance service.
+type Image interface {
+	// ListImages lists all images.
+	ListImages(ctx context.Context, opts images.ListOptsBuilder) ([]images.Image, error)
 }
@@ -5,20 +5,105 @@
 package client
 
 import (
+	"errors"
+	"fmt"
+	"time"
+
 	"github.com/gardener/machine-controller-manager/pkg/util/provider/metrics"
+	"github.com/gophercloud/gophercloud"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
-// onCall records a request to the specified service.
-//
-//nolint:unparam
-func onCall(service string) {
+var (
+	// apiOperationRequestCount counts the calls of client operations, partitioned by provider, service, operation and
+	// the class of the HTTP status code.
+	apiOperationRequestCount = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "mcm",
+		Subsystem: "cloud_api",
+		Name:      "operation_requests_total",
+		Help:      "Number of Cloud Service API operations, partitioned by provider, service, operation, and status class.",
+	}, []string{"provider", "service", "operation", "status_class"},
+	)
+
+	// apiOperationDuration observes the latency of client operations, partitioned by provider, service, operation and the
+	// class of the HTTP status code.
+	apiOperationDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "mcm",
+		Subsystem: "cloud_api",
+		Name:      "operation_duration_seconds",
+		Help:      "Latency of Cloud Service API operations, partitioned by provider, service, operation, and status class.",
+		Buckets:   prometheus.ExponentialBuckets(0.01, 2, 12),
+	}, []string{"provider", "service", "operation", "status_class"},
+	)
+
+	// apiRetriedRequestCount counts the requests retried after transient failures, partitioned by provider and service.
+	apiRetriedRequestCount = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "mcm",
+		Subsystem: "cloud_api",
+		Name:      "requests_retried_total",
+		Help:      "Number of retried Cloud Service API requests, partitioned by provider, and service.",
+	}, []string{"provider", "service"},
+	)
+
+	// apiRateLimiterWaitDuration observes the time requests wait for the client-side rate limiter, partitioned by
+	// provider, service and kind of request.
+	apiRateLimiterWaitDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "mcm",
+		Subsystem: "cloud_api",
+		Name:      "rate_limiter_wait_seconds",
+		Help:      "Time Cloud Service API requests waited for the client-side rate limiter, partitioned by provider, service, and kind.",
+		Buckets:   prometheus.ExponentialBuckets(0.001, 4, 9),
+	}, []string{"provider", "service", "kind"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(apiOperationRequestCount)
+	prometheus.MustRegister(apiOperationDuration)
+	prometheus.MustRegister(apiRetriedRequestCount)
+	prometheus.MustRegister(apiRateLimiterWaitDuration)
+}
+
+// onCall records a call of the operation of the specified service, which started at the given time and returned the
+// given error.
+func onCall(service, operation string, start time.Time, err error) {
 	metrics.APIRequestCount.With(prometheus.Labels{"provider": "openstack", "service": service}).Inc()
+
+	labels := prometheus.Labels{"provider": "openstack", "service": service, "operation": operation, "status_class": statusClass(err)}
+	apiOperationRequestCount.With(labels).Inc()
+	apiOperationDuration.With(labels).Observe(time.Since(start).Seconds())
 }
 
 // onFailure records a failure in the request to the specified service.
-//
-//nolint:unparam
 func onFailure(service string) {
 	metrics.APIFailedRequestCount.With(prometheus.Labels{"provider": "openstack", "service": service}).Inc()
 }
+
+// onRetry records a retry of a request to the specified service.
+func onRetry(service string) {
+	apiRetriedRequestCount.With(prometheus.Labels{"provider": "openstack", "service": service}).Inc()
+}
+
+// onRateLimiterWait records the time a request to the specified service waited for the rate limiter.
+func onRateLimiterWait(service, kind string, wait time.Duration) {
+	apiRateLimiterWaitDuration.With(prometheus.Labels{"provider": "openstack", "service": service, "kind": kind}).Observe(wait.Seconds())
+}
+
+// statusClass returns the class of the HTTP status code the error was caused by, e.g. "4xx". Errors without a response
+// from the service, e.g. connection failures, are classified as "error".
+func statusClass(err error) string {
+	if err == nil {
+		return "2xx"
+	}
+
+	var codeErr gophercloud.StatusCodeError
+	if errors.As(err, &codeErr) {
+		return fmt.Sprintf("%dxx", codeErr.GetStatusCode()/100)
+	}
+
+	// resolving names fails with these errors after a successful list request
+	if errors.As(err, &gophercloud.ErrResourceNotFound{}) || errors.As(err, &gophercloud.ErrMultipleResourcesFound{}) {
+		return "2xx"
+	}
+	return "error"
+}
@@ -0,0 +1,74 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gophercloud/gophercloud"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// metricValue returns the value of the counter or gauge, or the sample count of the histogram, with the given name and
+// labels from the default registry. It returns zero if the metric has not been recorded.
+func metricValue(name string, labels prometheus.Labels) float64 {
+	families, err := prometheus.DefaultGatherer.Gather()
+	Expect(err).NotTo(HaveOccurred())
+
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+	metrics:
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if value, ok := labels[label.GetName()]; ok && value != label.GetValue() {
+					continue metrics
+				}
+			}
+			switch {
+			case metric.GetCounter() != nil:
+				return metric.GetCounter().GetValue()
+			case metric.GetGauge() != nil:
+				return metric.GetGauge().GetValue()
+			case metric.GetHistogram() != nil:
+				return float64(metric.GetHistogram().GetSampleCount())
+			}
+		}
+	}
+	return 0
+}
+
+var _ = Describe("Metrics", func() {
+	DescribeTable("#statusClass",
+		func(err error, expected string) {
+			Expect(statusClass(err)).To(Equal(expected))
+		},
+		Entry("success", nil, "2xx"),
+		Entry("not found", gophercloud.ErrDefault404{ErrUnexpectedResponseCode: gophercloud.ErrUnexpectedResponseCode{Actual: 404}}, "4xx"),
+		Entry("server error", gophercloud.ErrDefault500{ErrUnexpectedResponseCode: gophercloud.ErrUnexpectedResponseCode{Actual: 500}}, "5xx"),
+		Entry("server error with request ID", &RequestError{RequestID: "req-1", Err: gophercloud.ErrDefault503{ErrUnexpectedResponseCode: gophercloud.ErrUnexpectedResponseCode{Actual: 503}}}, "5xx"),
+		Entry("unresolved name", gophercloud.ErrResourceNotFound{Name: "foo", ResourceType: "flavor"}, "2xx"),
+		Entry("ambiguous name", gophercloud.ErrMultipleResourcesFound{Name: "foo", Count: 2, ResourceType: "flavor"}, "2xx"),
+		Entry("connection failure", fmt.Errorf("dial tcp: %w", errors.New("connection refused")), "error"),
+	)
+
+	It("should record the operation by status class", func() {
+		labels := prometheus.Labels{"provider": "openstack", "service": "test", "operation": "GetServer", "status_class": "4xx"}
+		count := metricValue("mcm_cloud_api_operation_requests_total", labels)
+		observations := metricValue("mcm_cloud_api_operation_duration_seconds", labels)
+
+		onCall("test", "GetServer", time.Now(), gophercloud.ErrDefault404{ErrUnexpectedResponseCode: gophercloud.ErrUnexpectedResponseCode{Actual: 404}})
+		Expect(metricValue("mcm_cloud_api_operation_requests_total", labels)).To(Equal(count + 1))
+		Expect(metricValue("mcm_cloud_api_operation_duration_seconds", labels)).To(Equal(observations + 1))
+
+		labels["status_class"] = "2xx"
+		Expect(metricValue("mcm_cloud_api_operation_requests_total", labels)).To(BeZero())
+	})
+})
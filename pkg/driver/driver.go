@@ -7,17 +7,19 @@ package driver
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/gardener/machine-controller-manager/pkg/util/provider/driver"
 	"github.com/gardener/machine-controller-manager/pkg/util/provider/machinecodes/codes"
 	"github.com/gardener/machine-controller-manager/pkg/util/provider/machinecodes/status"
+	"go.opentelemetry.io/otel/trace"
 	"k8s.io/klog/v2"
 
 	"github.com/gardener/machine-controller-manager-provider-openstack/pkg/apis/cloudprovider"
 	"github.com/gardener/machine-controller-manager-provider-openstack/pkg/apis/validation"
-	"github.com/gardener/machine-controller-manager-provider-openstack/pkg/client"
 	"github.com/gardener/machine-controller-manager-provider-openstack/pkg/driver/executor"
+	"github.com/gardener/machine-controller-manager-provider-openstack/pkg/tracing"
 )
 
 const (
@@ -39,10 +41,13 @@ const (
 // It is optionally expected by the safety controller to use an identification mechanism to map the VM Created by a providerSpec.
 // These could be done using tag(s)/resource-groups etc.
 // This logic is used by safety controller to delete orphan VMs which are not backed by any machine CRD
-func (p *OpenstackDriver) CreateMachine(ctx context.Context, req *driver.CreateMachineRequest) (*driver.CreateMachineResponse, error) {
+func (p *OpenstackDriver) CreateMachine(ctx context.Context, req *driver.CreateMachineRequest) (_ *driver.CreateMachineResponse, err error) {
 	klog.V(2).Infof("CreateMachine request has been received for %q", req.Machine.Name)
 	defer klog.V(2).Infof("CreateMachine request has been processed for %q", req.Machine.Name)
 
+	ctx, span := tracing.Start(ctx, "CreateMachine", trace.WithAttributes(tracing.MachineName(req.Machine.Name)))
+	defer func() { tracing.End(span, err) }()
+
 	// Check if incoming provider in the MachineClass is a provider we support
 	if req.MachineClass.Provider != openstackProvider {
 		err := fmt.Errorf("requested for Provider '%s', we only support '%s'", req.MachineClass.Provider, openstackProvider)
@@ -59,22 +64,22 @@ func (p *OpenstackDriver) CreateMachine(ctx context.Context, req *driver.CreateM
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	factory, err := client.NewFactoryFromSecret(req.Secret)
+	factory, err := p.clientCache.GetOrCreate(req.Secret)
 	if err != nil {
 		klog.Errorf("failed to construct OpenStack client: %v", err)
-		return nil, status.Error(mapErrorToCode(err), fmt.Sprintf("failed to construct OpenStack client: %v", err))
+		return nil, status.Error(recordErrorCode("CreateMachine", providerConfig.Spec.Region, err), fmt.Sprintf("failed to construct OpenStack client: %v", err))
 	}
 
-	ex, err := executor.NewExecutor(factory, providerConfig)
+	ex, err := executor.NewExecutor(factory, providerConfig, p.timeouts)
 	if err != nil {
 		klog.Errorf("failed to construct context for the request: %v", err)
-		return nil, status.Error(mapErrorToCode(err), fmt.Sprintf("failed to construct context for the request: %v", err))
+		return nil, status.Error(recordErrorCode("CreateMachine", providerConfig.Spec.Region, err), fmt.Sprintf("failed to construct context for the request: %v", err))
 	}
 
 	providerID, err := ex.CreateMachine(ctx, req.Machine.Name, req.Secret.Data[cloudprovider.UserData])
 	if err != nil {
 		klog.Errorf("machine creation for machine %q failed with: %v", req.Machine.Name, err)
-		return nil, status.Error(mapErrorToCode(err), err.Error())
+		return nil, status.Error(recordErrorCode("CreateMachine", providerConfig.Spec.Region, err), err.Error())
 	}
 
 	return &driver.CreateMachineResponse{
@@ -83,17 +88,64 @@ func (p *OpenstackDriver) CreateMachine(ctx context.Context, req *driver.CreateM
 	}, nil
 }
 
-// InitializeMachine handles VM initialization for openstack VM's. Currently, un-implemented.
-func (p *OpenstackDriver) InitializeMachine(_ context.Context, _ *driver.InitializeMachineRequest) (*driver.InitializeMachineResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "Openstack Provider does not yet implement InitializeMachine")
+// InitializeMachine handles VM initialization for openstack VM's. It performs the configuration steps that require an
+// active server, e.g. patching the server ports for the pod network.
+func (p *OpenstackDriver) InitializeMachine(ctx context.Context, req *driver.InitializeMachineRequest) (_ *driver.InitializeMachineResponse, err error) {
+	klog.V(2).Infof("InitializeMachine request has been received for %q", req.Machine.Name)
+	defer klog.V(2).Infof("InitializeMachine request has been processed for %q", req.Machine.Name)
+
+	ctx, span := tracing.Start(ctx, "InitializeMachine", trace.WithAttributes(tracing.MachineName(req.Machine.Name)))
+	defer func() { tracing.End(span, err) }()
+
+	// Check if incoming provider in the MachineClass is a provider we support
+	if req.MachineClass.Provider != openstackProvider {
+		err := fmt.Errorf("requested for Provider '%s', we only support '%s'", req.MachineClass.Provider, openstackProvider)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	providerConfig, err := p.decodeProviderSpec(req.MachineClass.ProviderSpec)
+	if err != nil {
+		klog.Errorf("decoding provider spec for machine class %q failed with: %v", req.MachineClass.Name, err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := validation.ValidateRequest(providerConfig, req.Secret); err != nil {
+		klog.Errorf("validating request for machine %q failed with: %v", req.Machine.Name, err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	factory, err := p.clientCache.GetOrCreate(req.Secret)
+	if err != nil {
+		klog.Errorf("failed to construct OpenStack client: %v", err)
+		return nil, status.Error(codes.Uninitialized, fmt.Sprintf("failed to construct OpenStack client: %v", err))
+	}
+
+	ex, err := executor.NewExecutor(factory, providerConfig, p.timeouts)
+	if err != nil {
+		klog.Errorf("failed to construct context for the request: %v", err)
+		return nil, status.Error(codes.Uninitialized, fmt.Sprintf("failed to construct context for the request: %v", err))
+	}
+
+	providerID, err := ex.InitializeMachine(ctx, req.Machine.Name, req.Machine.Spec.ProviderID)
+	if err != nil {
+		klog.Errorf("machine initialization for machine %q failed with: %v", req.Machine.Name, err)
+		return nil, status.Error(mapInitializeErrorToCode(err), err.Error())
+	}
+
+	return &driver.InitializeMachineResponse{
+		ProviderID: providerID,
+		NodeName:   req.Machine.Name,
+	}, nil
 }
 
 // DeleteMachine handles a machine deletion request
-func (p *OpenstackDriver) DeleteMachine(ctx context.Context, req *driver.DeleteMachineRequest) (*driver.DeleteMachineResponse, error) {
+func (p *OpenstackDriver) DeleteMachine(ctx context.Context, req *driver.DeleteMachineRequest) (_ *driver.DeleteMachineResponse, err error) {
 	// Log messages to track delete request
 	klog.V(2).Infof("DeleteMachine request has been received for %q", req.Machine.Name)
 	defer klog.V(2).Infof("DeleteMachine request has been processed for %q", req.Machine.Name)
 
+	ctx, span := tracing.Start(ctx, "DeleteMachine", trace.WithAttributes(tracing.MachineName(req.Machine.Name)))
+	defer func() { tracing.End(span, err) }()
+
 	// Check if incoming provider in the MachineClass is a provider we support
 	if req.MachineClass.Provider != openstackProvider {
 		err := fmt.Errorf("requested for Provider '%s', we only support '%s'", req.MachineClass.Provider, openstackProvider)
@@ -110,42 +162,93 @@ func (p *OpenstackDriver) DeleteMachine(ctx context.Context, req *driver.DeleteM
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	factory, err := client.NewFactoryFromSecret(req.Secret)
+	factory, err := p.clientCache.GetOrCreate(req.Secret)
 	if err != nil {
 		klog.Errorf("failed to construct OpenStack client: %v", err)
-		return nil, status.Error(mapErrorToCode(err), fmt.Sprintf("failed to construct OpenStack client: %v", err))
+		return nil, status.Error(recordErrorCode("DeleteMachine", providerConfig.Spec.Region, err), fmt.Sprintf("failed to construct OpenStack client: %v", err))
 	}
 
-	ex, err := executor.NewExecutor(factory, providerConfig)
+	ex, err := executor.NewExecutor(factory, providerConfig, p.timeouts)
 	if err != nil {
 		klog.Errorf("failed to construct context for the request: %v", err)
-		return nil, status.Error(mapErrorToCode(err), fmt.Sprintf("failed to construct context for the request: %v", err))
+		return nil, status.Error(recordErrorCode("DeleteMachine", providerConfig.Spec.Region, err), fmt.Sprintf("failed to construct context for the request: %v", err))
 	}
 
 	err = ex.DeleteMachine(ctx, req.Machine.Name, req.Machine.Spec.ProviderID)
 	if err != nil {
-		return nil, status.Error(mapErrorToCode(err), err.Error())
+		return nil, status.Error(recordErrorCode("DeleteMachine", providerConfig.Spec.Region, err), err.Error())
 	}
 	return &driver.DeleteMachineResponse{}, nil
 }
 
 // GetMachineStatus handles a machine get status request
-func (p *OpenstackDriver) GetMachineStatus(_ context.Context, req *driver.GetMachineStatusRequest) (*driver.GetMachineStatusResponse, error) {
+func (p *OpenstackDriver) GetMachineStatus(ctx context.Context, req *driver.GetMachineStatusRequest) (_ *driver.GetMachineStatusResponse, err error) {
 	// Log messages to track start and end of request
 	klog.V(2).Infof("GetMachineStatus request has been received for %q", req.Machine.Name)
-	defer klog.V(2).Infof("GetMachineStatus is not implemented")
+	defer klog.V(2).Infof("GetMachineStatus request has been processed for %q", req.Machine.Name)
 
-	return nil, status.Error(codes.Unimplemented, "method not implemented")
+	ctx, span := tracing.Start(ctx, "GetMachineStatus", trace.WithAttributes(tracing.MachineName(req.Machine.Name)))
+	defer func() { tracing.End(span, err) }()
+
+	// Check if incoming provider in the MachineClass is a provider we support
+	if req.MachineClass.Provider != openstackProvider {
+		err := fmt.Errorf("requested for Provider '%s', we only support '%s'", req.MachineClass.Provider, openstackProvider)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	providerConfig, err := p.decodeProviderSpec(req.MachineClass.ProviderSpec)
+	if err != nil {
+		klog.Errorf("decoding provider spec for machine class %q failed with: %v", req.MachineClass.Name, err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := validation.ValidateRequest(providerConfig, req.Secret); err != nil {
+		klog.Errorf("validating request for machine %q failed with: %v", req.Machine.Name, err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	factory, err := p.clientCache.GetOrCreate(req.Secret)
+	if err != nil {
+		klog.Errorf("failed to construct OpenStack client: %v", err)
+		return nil, status.Error(recordErrorCode("GetMachineStatus", providerConfig.Spec.Region, err), fmt.Sprintf("failed to construct OpenStack client: %v", err))
+	}
+
+	ex, err := executor.NewExecutor(factory, providerConfig, p.timeouts)
+	if err != nil {
+		klog.Errorf("failed to construct context for the request: %v", err)
+		return nil, status.Error(recordErrorCode("GetMachineStatus", providerConfig.Spec.Region, err), fmt.Sprintf("failed to construct context for the request: %v", err))
+	}
+
+	providerID, err := ex.GetMachineStatus(ctx, req.Machine.Name, req.Machine.Spec.ProviderID)
+	if errors.Is(err, executor.ErrNotInitialized) {
+		// the response is still required to trigger the initialization of the existing machine
+		klog.V(2).Infof("machine %q is not initialized: %v", req.Machine.Name, err)
+		return &driver.GetMachineStatusResponse{
+			ProviderID: providerID,
+			NodeName:   req.Machine.Name,
+		}, status.Error(codes.Uninitialized, err.Error())
+	}
+	if err != nil {
+		klog.V(2).Infof("getting status for machine %q failed with: %v", req.Machine.Name, err)
+		return nil, status.Error(recordErrorCode("GetMachineStatus", providerConfig.Spec.Region, err), err.Error())
+	}
+
+	return &driver.GetMachineStatusResponse{
+		ProviderID: providerID,
+		NodeName:   req.Machine.Name,
+	}, nil
 }
 
 // ListMachines lists all the machines possibly created by a providerSpec
 // Identifying machines created by a given providerSpec depends on the OPTIONAL IMPLEMENTATION LOGIC
 // you have used to identify machines created by a providerSpec. It could be tags/resource-groups etc
-func (p *OpenstackDriver) ListMachines(ctx context.Context, req *driver.ListMachinesRequest) (*driver.ListMachinesResponse, error) {
+func (p *OpenstackDriver) ListMachines(ctx context.Context, req *driver.ListMachinesRequest) (_ *driver.ListMachinesResponse, err error) {
 	// Log messages to track start and end of request
 	klog.V(2).Infof("ListMachines request has been received for %q", req.MachineClass.Name)
 	defer klog.V(2).Infof("ListMachines request has been processed for %q", req.MachineClass.Name)
 
+	ctx, span := tracing.Start(ctx, "ListMachines", trace.WithAttributes(tracing.MachineClassName(req.MachineClass.Name)))
+	defer func() { tracing.End(span, err) }()
+
 	// Check if incoming provider in the MachineClass is a provider we support
 	if req.MachineClass.Provider != openstackProvider {
 		err := fmt.Errorf("requested for Provider '%s', we only support '%s'", req.MachineClass.Provider, openstackProvider)
@@ -163,21 +266,21 @@ func (p *OpenstackDriver) ListMachines(ctx context.Context, req *driver.ListMach
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	factory, err := client.NewFactoryFromSecret(req.Secret)
+	factory, err := p.clientCache.GetOrCreate(req.Secret)
 	if err != nil {
 		klog.Errorf("failed to construct OpenStack client: %v", err)
-		return nil, status.Error(mapErrorToCode(err), fmt.Sprintf("failed to construct OpenStack client: %v", err))
+		return nil, status.Error(recordErrorCode("ListMachines", providerConfig.Spec.Region, err), fmt.Sprintf("failed to construct OpenStack client: %v", err))
 	}
 
-	ex, err := executor.NewExecutor(factory, providerConfig)
+	ex, err := executor.NewExecutor(factory, providerConfig, p.timeouts)
 	if err != nil {
 		klog.Errorf("failed to construct context for the request: %v", err)
-		return nil, status.Error(mapErrorToCode(err), fmt.Sprintf("failed to construct context for the request: %v", err))
+		return nil, status.Error(recordErrorCode("ListMachines", providerConfig.Spec.Region, err), fmt.Sprintf("failed to construct context for the request: %v", err))
 	}
 
 	machines, err := ex.ListMachines(ctx)
 	if err != nil {
-		return nil, status.Error(mapErrorToCode(err), fmt.Sprintf("listing machines for machine class %q failed with: %v", req.MachineClass.Name, err))
+		return nil, status.Error(recordErrorCode("ListMachines", providerConfig.Spec.Region, err), fmt.Sprintf("listing machines for machine class %q failed with: %v", req.MachineClass.Name, err))
 	}
 	if len(machines) == 0 {
 		klog.V(3).Infof("no machines found for machine class: %q", req.MachineClass.Name)
@@ -189,11 +292,14 @@ func (p *OpenstackDriver) ListMachines(ctx context.Context, req *driver.ListMach
 }
 
 // GetVolumeIDs returns a list of Volume IDs for all PV Specs for whom an provider volume was found
-func (p *OpenstackDriver) GetVolumeIDs(_ context.Context, req *driver.GetVolumeIDsRequest) (*driver.GetVolumeIDsResponse, error) {
+func (p *OpenstackDriver) GetVolumeIDs(ctx context.Context, req *driver.GetVolumeIDsRequest) (*driver.GetVolumeIDsResponse, error) {
 	// Log messages to track start and end of request
 	klog.V(2).Infof("GetVolumeIDs request has been received for %q", req.PVSpecs)
 	defer klog.V(2).Infof("GetVolumeIDs request has been processed for %q", req.PVSpecs)
 
+	_, span := tracing.Start(ctx, "GetVolumeIDs")
+	defer span.End()
+
 	names := make([]string, 0)
 	for _, spec := range req.PVSpecs {
 		if spec.Cinder != nil {
@@ -0,0 +1,238 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	machinev1alpha1 "github.com/gardener/machine-controller-manager/pkg/apis/machine/v1alpha1"
+	mcmdriver "github.com/gardener/machine-controller-manager/pkg/util/provider/driver"
+	"github.com/gardener/machine-controller-manager/pkg/util/provider/machinecodes/codes"
+	"github.com/gardener/machine-controller-manager/pkg/util/provider/machinecodes/status"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/gardener/machine-controller-manager-provider-openstack/pkg/apis/cloudprovider"
+	"github.com/gardener/machine-controller-manager-provider-openstack/pkg/apis/openstack/v1alpha1"
+	"github.com/gardener/machine-controller-manager-provider-openstack/pkg/client"
+	"github.com/gardener/machine-controller-manager-provider-openstack/pkg/driver"
+	"github.com/gardener/machine-controller-manager-provider-openstack/pkg/driver/executor"
+)
+
+const (
+	clusterTag = cloudprovider.ServerTagClusterPrefix + "shoot"
+	roleTag    = cloudprovider.ServerTagRolePrefix + "node"
+)
+
+// fakeOpenStack is a minimal OpenStack cloud, which issues tokens for any credentials and serves the servers it knows.
+type fakeOpenStack struct {
+	server *httptest.Server
+
+	mutex   sync.Mutex
+	servers map[string]map[string]interface{}
+}
+
+func newFakeOpenStack() *fakeOpenStack {
+	o := &fakeOpenStack{
+		servers: map[string]map[string]interface{}{},
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v3/auth/tokens", o.createToken)
+	mux.HandleFunc("GET /compute/v2.1/servers/{id}", o.getServer)
+	o.server = httptest.NewServer(mux)
+	return o
+}
+
+func (o *fakeOpenStack) addServer(id, serverStatus string, metadata map[string]string) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	o.servers[id] = map[string]interface{}{
+		"id":       id,
+		"name":     "machine",
+		"status":   serverStatus,
+		"metadata": metadata,
+	}
+}
+
+func (o *fakeOpenStack) createToken(w http.ResponseWriter, _ *http.Request) {
+	catalogEntry := func(serviceType, path string) map[string]interface{} {
+		return map[string]interface{}{
+			"type": serviceType,
+			"endpoints": []interface{}{
+				map[string]string{"interface": "public", "region_id": "region", "url": o.server.URL + path},
+			},
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Subject-Token", "token")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"token": map[string]interface{}{
+			"expires_at": time.Now().Add(time.Hour).UTC().Format(time.RFC3339),
+			"project":    map[string]string{"id": "project-id", "name": "project"},
+			"catalog": []interface{}{
+				catalogEntry("compute", "/compute/v2.1"),
+				catalogEntry("network", "/network"),
+				catalogEntry("volumev3", "/volume/v3/project-id"),
+			},
+		},
+	})
+}
+
+func (o *fakeOpenStack) getServer(w http.ResponseWriter, r *http.Request) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	server, ok := o.servers[r.PathValue("id")]
+	if !ok {
+		http.Error(w, `{"itemNotFound": {"code": 404, "message": "Instance could not be found."}}`, http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"server": server})
+}
+
+var _ = Describe("OpenstackDriver", func() {
+	var (
+		cloud *fakeOpenStack
+		drv   mcmdriver.Driver
+
+		machine      *machinev1alpha1.Machine
+		machineClass *machinev1alpha1.MachineClass
+		secret       *corev1.Secret
+	)
+
+	BeforeEach(func() {
+		cloud = newFakeOpenStack()
+		DeferCleanup(cloud.server.Close)
+
+		drv = driver.NewOpenstackDriver(driver.Decoder, executor.Timeouts{}, client.RateLimits{}, client.DefaultHTTPOptions())
+
+		providerSpec, err := json.Marshal(&v1alpha1.MachineProviderConfig{
+			TypeMeta: metav1.TypeMeta{APIVersion: v1alpha1.SchemeGroupVersion.String(), Kind: "MachineProviderConfig"},
+			Spec: v1alpha1.MachineProviderConfigSpec{
+				ImageName:        "image",
+				Region:           "region",
+				AvailabilityZone: "zone",
+				FlavorName:       "flavor",
+				KeyName:          "key",
+				NetworkID:        "network-id",
+				PodNetworkCIDRs:  []string{"10.0.0.0/8"},
+				Tags:             map[string]string{clusterTag: "1", roleTag: "1"},
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		machineClass = &machinev1alpha1.MachineClass{
+			ObjectMeta:   metav1.ObjectMeta{Name: "machine-class"},
+			Provider:     "OpenStack",
+			ProviderSpec: runtime.RawExtension{Raw: providerSpec},
+		}
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "cloudprovider", Namespace: "default"},
+			Data: map[string][]byte{
+				cloudprovider.OpenStackAuthURL:    []byte(cloud.server.URL + "/v3"),
+				cloudprovider.OpenStackUsername:   []byte("user"),
+				cloudprovider.OpenStackPassword:   []byte("password"),
+				cloudprovider.OpenStackDomainName: []byte("domain"),
+				cloudprovider.OpenStackTenantName: []byte("project"),
+				cloudprovider.UserData:            []byte("#cloud-config"),
+			},
+		}
+		machine = &machinev1alpha1.Machine{
+			ObjectMeta: metav1.ObjectMeta{Name: "machine"},
+			Spec:       machinev1alpha1.MachineSpec{ProviderID: "openstack:///region/server-id"},
+		}
+	})
+
+	code := func(err error) codes.Code {
+		s, ok := status.FromError(err)
+		Expect(ok).To(BeTrue(), fmt.Sprintf("expected a status error, got %v", err))
+		return s.Code()
+	}
+
+	Describe("#GetMachineStatus", func() {
+		getMachineStatus := func() (*mcmdriver.GetMachineStatusResponse, error) {
+			return drv.GetMachineStatus(context.Background(), &mcmdriver.GetMachineStatusRequest{
+				Machine:      machine,
+				MachineClass: machineClass,
+				Secret:       secret,
+			})
+		}
+
+		DescribeTable("should map the server status to a machine error code",
+			func(serverStatus string, expected codes.Code) {
+				cloud.addServer("server-id", serverStatus, map[string]string{clusterTag: "1", roleTag: "1"})
+
+				_, err := getMachineStatus()
+				Expect(code(err)).To(Equal(expected))
+			},
+			Entry("ERROR", client.ServerStatusError, codes.Unavailable),
+			Entry("SHUTOFF", client.ServerStatusShutoff, codes.Unavailable),
+		)
+
+		It("should return NotFound if the server does not exist", func() {
+			_, err := getMachineStatus()
+			Expect(code(err)).To(Equal(codes.NotFound))
+		})
+
+		It("should return NotFound if the server is owned by another cluster", func() {
+			cloud.addServer("server-id", client.ServerStatusActive, map[string]string{cloudprovider.ServerTagClusterPrefix + "other": "1", roleTag: "1"})
+
+			_, err := getMachineStatus()
+			Expect(code(err)).To(Equal(codes.NotFound))
+		})
+
+		It("should return the provider ID of a server which is still building", func() {
+			cloud.addServer("server-id", client.ServerStatusBuild, map[string]string{clusterTag: "1", roleTag: "1"})
+
+			resp, err := getMachineStatus()
+			Expect(code(err)).To(Equal(codes.Uninitialized))
+			Expect(resp).To(Equal(&mcmdriver.GetMachineStatusResponse{ProviderID: "openstack:///region/server-id", NodeName: "machine"}))
+		})
+
+		It("should reject other providers", func() {
+			machineClass.Provider = "AWS"
+
+			_, err := getMachineStatus()
+			Expect(code(err)).To(Equal(codes.InvalidArgument))
+		})
+	})
+
+	Describe("#InitializeMachine", func() {
+		initializeMachine := func() error {
+			_, err := drv.InitializeMachine(context.Background(), &mcmdriver.InitializeMachineRequest{
+				Machine:      machine,
+				MachineClass: machineClass,
+				Secret:       secret,
+			})
+			return err
+		}
+
+		DescribeTable("should map the server status to a machine error code",
+			func(serverStatus string, expected codes.Code) {
+				cloud.addServer("server-id", serverStatus, map[string]string{clusterTag: "1", roleTag: "1"})
+
+				Expect(code(initializeMachine())).To(Equal(expected))
+			},
+			Entry("ERROR", client.ServerStatusError, codes.Unavailable),
+			Entry("SHUTOFF", client.ServerStatusShutoff, codes.Unavailable),
+			Entry("BUILD", client.ServerStatusBuild, codes.Uninitialized),
+		)
+
+		It("should return NotFound if the server does not exist", func() {
+			Expect(code(initializeMachine())).To(Equal(codes.NotFound))
+		})
+	})
+})
@@ -15,6 +15,13 @@ import (
 //	"No valid host was found. There are not enough hosts available."
 const NoValidHost = "No valid host was found"
 
+// QuotaExceeded is a part of the error message returned when the server cannot be created because a quota of the
+// project, e.g. for cores or RAM, is exhausted.
+// Matches:
+//
+//	"Quota exceeded for cores: Requested 8, but already used 96 of 100 cores"
+const QuotaExceeded = "Quota exceeded"
+
 var (
 	// ErrNotFound is returned when the requested resource could not be found.
 	ErrNotFound = fmt.Errorf("resource not found")
@@ -23,4 +30,14 @@ var (
 	// For example, reverse lookups from names to IDs may yield multiple matches because names are not unique in most
 	// OpenStack resources. In case this case, where a unique ID could not be determined an ErrMultipleFound is returned.
 	ErrMultipleFound = fmt.Errorf("multiple resources found")
+
+	// ErrServerFailed is returned when the server backing a machine is in ERROR status.
+	ErrServerFailed = fmt.Errorf("server is in error status")
+
+	// ErrServerShutoff is returned when the server backing a machine is stopped.
+	ErrServerShutoff = fmt.Errorf("server is shut off")
+
+	// ErrNotInitialized is returned when the server backing a machine exists, but the post-boot initialization has not
+	// been completed yet.
+	ErrNotInitialized = fmt.Errorf("server is not initialized")
 )
@@ -8,6 +8,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/gophercloud/gophercloud/openstack/blockstorage/v3/volumes"
@@ -15,14 +17,18 @@ import (
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/keypairs"
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/schedulerhints"
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/floatingips"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/ports"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"k8s.io/apimachinery/pkg/util/sets"
-	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/klog/v2"
 	"k8s.io/utils/ptr"
 
 	api "github.com/gardener/machine-controller-manager-provider-openstack/pkg/apis/openstack"
 	"github.com/gardener/machine-controller-manager-provider-openstack/pkg/client"
+	"github.com/gardener/machine-controller-manager-provider-openstack/pkg/tracing"
 )
 
 // Executor concretely handles the execution of requests to the machine controller. Executor is responsible
@@ -31,11 +37,15 @@ type Executor struct {
 	Compute client.Compute
 	Network client.Network
 	Storage client.Storage
-	Config  *api.MachineProviderConfig
+	// Image is only set if the image is selected with an image selector.
+	Image  client.Image
+	Config *api.MachineProviderConfig
+	// Timeouts are the driver-level default timeouts, which can be overridden in the provider spec.
+	Timeouts Timeouts
 }
 
 // NewExecutor returns a new instance of Executor.
-func NewExecutor(factory *client.Factory, config *api.MachineProviderConfig) (*Executor, error) {
+func NewExecutor(factory *client.Factory, config *api.MachineProviderConfig, timeouts Timeouts) (*Executor, error) {
 	computeClient, err := factory.Compute(client.WithRegion(config.Spec.Region))
 	if err != nil {
 		klog.Errorf("failed to create compute client for executor: %v", err)
@@ -53,62 +63,189 @@ func NewExecutor(factory *client.Factory, config *api.MachineProviderConfig) (*E
 	}
 
 	ex := &Executor{
-		Compute: computeClient,
-		Network: networkClient,
-		Storage: storageClient,
-		Config:  config,
+		Compute:  computeClient,
+		Network:  networkClient,
+		Storage:  storageClient,
+		Config:   config,
+		Timeouts: timeouts,
+	}
+	if config.Spec.ImageSelector != nil {
+		ex.Image, err = factory.Image(client.WithRegion(config.Spec.Region))
+		if err != nil {
+			klog.Errorf("failed to create image client for executor: %v", err)
+			return nil, err
+		}
 	}
 	return ex, nil
 }
 
 // CreateMachine creates a new OpenStack server instance and waits until it reports "ACTIVE".
 // If there is an error during the build process, or if the building phase timeouts, it will delete any artifacts created.
+// If the server cannot be created because of insufficient capacity, the creation is retried with the fallback flavors and
+// in the fallback availability zones. If a quota is exhausted, only the fallback flavors are tried.
+// Steps that require an "ACTIVE" server are not part of the creation and are performed by InitializeMachine.
 func (ex *Executor) CreateMachine(ctx context.Context, machineName string, userData []byte) (string, error) {
-	var (
-		server *servers.Server
-		err    error
-	)
-
+	// data volumes which are kept on termination are not deleted by DeleteMachine, the ones created by this call are deleted
+	// explicitly if the creation fails.
+	createdVolumes := sets.New[string]()
 	deleteOnFail := func(err error) error {
 		klog.Infof("attempting to delete server [Name=%q] after unsuccessful create operation with error: %v", machineName, err)
 		if errIn := ex.DeleteMachine(ctx, machineName, ""); errIn != nil {
 			return fmt.Errorf("error deleting server [Name=%q] after unsuccessful creation attempt: %v. Original error: %w", machineName, errIn, err)
 		}
+		for _, name := range sets.List(createdVolumes) {
+			if errIn := ex.deleteVolume(ctx, name); errIn != nil {
+				return fmt.Errorf("error deleting volume [Name=%q] after unsuccessful creation attempt: %v. Original error: %w", name, errIn, err)
+			}
+		}
 		return err
 	}
 
-	server, err = ex.getMachineByName(ctx, machineName)
+	server, err := ex.getMachineByName(ctx, machineName)
 	if err == nil {
 		klog.Infof("found existing server [Name=%q, ID=%q]", machineName, server.ID)
+		if err := ex.waitForServerActive(ctx, server.ID); err != nil {
+			return "", deleteOnFail(err)
+		}
+		return encodeProviderID(ex.Config.Spec.Region, server.ID), nil
 	} else if !errors.Is(err, ErrNotFound) {
 		return "", err
-	} else {
-		// clean-up function when creation fails in an intermediate step
-		serverNetworks, err := ex.resolveServerNetworks(ctx, machineName)
-		if err != nil {
-			return "", deleteOnFail(fmt.Errorf("failed to resolve server [Name=%q] networks: %w", machineName, err))
+	}
+
+	placements := ex.placements()
+	for i, p := range placements {
+		server, err = ex.createServer(ctx, machineName, userData, p, createdVolumes)
+		if err == nil {
+			return encodeProviderID(ex.Config.Spec.Region, server.ID), nil
 		}
 
-		server, err = ex.deployServer(ctx, machineName, userData, serverNetworks)
-		if err != nil {
-			return "", deleteOnFail(fmt.Errorf("failed to deploy server [Name=%q]: %w", machineName, err))
+		if i == len(placements)-1 || !shouldFallBack(err, p, placements[i+1]) {
+			return "", deleteOnFail(err)
+		}
+
+		next := placements[i+1]
+		klog.Warningf("insufficient capacity or quota for server [Name=%q] with %s, retrying with %s: %v", machineName, p, next, err)
+		if errIn := ex.DeleteMachine(ctx, machineName, ""); errIn != nil {
+			return "", fmt.Errorf("error deleting server [Name=%q] before retrying with %s: %v. Original error: %w", machineName, next, errIn, err)
+		}
+	}
+
+	return "", fmt.Errorf("no placement configured for server [Name=%q]", machineName)
+}
+
+// placement is a combination of availability zone and flavor a server can be created with.
+type placement struct {
+	availabilityZone string
+	flavorName       string
+}
+
+func (p placement) String() string {
+	return fmt.Sprintf("[AvailabilityZone=%q, Flavor=%q]", p.availabilityZone, p.flavorName)
+}
+
+// placements returns the placements to try in order. All flavors are tried in an availability zone before falling back to
+// the next availability zone, because moving a machine to another zone is more disruptive than substituting its flavor.
+func (ex *Executor) placements() []placement {
+	zones := append([]string{ex.Config.Spec.AvailabilityZone}, ex.Config.Spec.FallbackAvailabilityZones...)
+	flavors := append([]string{ex.Config.Spec.FlavorName}, ex.Config.Spec.FallbackFlavorNames...)
+
+	placements := make([]placement, 0, len(zones)*len(flavors))
+	for _, zone := range zones {
+		for _, flavor := range flavors {
+			placements = append(placements, placement{availabilityZone: zone, flavorName: flavor})
+		}
+	}
+	return placements
+}
+
+// shouldFallBack returns true if the creation with placement p failed with err in a way the next placement can resolve.
+// Insufficient capacity is specific to an availability zone, while a quota is per project and applies to all zones, so
+// only a fallback flavor in the same availability zone may still fit into an exhausted quota.
+func shouldFallBack(err error, p, next placement) bool {
+	if isCapacityError(err) {
+		return true
+	}
+	return isQuotaError(err) && next.availabilityZone == p.availabilityZone
+}
+
+// createServer creates a server with the given placement and waits until it reports "ACTIVE". Any artifacts created are
+// left for the caller to clean up, the names of the created data volumes which are kept on termination are added to
+// createdVolumes.
+func (ex *Executor) createServer(ctx context.Context, machineName string, userData []byte, p placement, createdVolumes sets.Set[string]) (*servers.Server, error) {
+	serverNetworks, err := ex.resolveServerNetworks(ctx, machineName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve server [Name=%q] networks: %w", machineName, err)
+	}
+
+	server, err := ex.deployServer(ctx, machineName, userData, serverNetworks, p, createdVolumes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to deploy server [Name=%q]: %w", machineName, err)
+	}
+
+	if err := ex.waitForServerActive(ctx, server.ID); err != nil {
+		return nil, err
+	}
+
+	if ex.Config.Spec.ManagedServerGroup != nil {
+		if err := ex.checkServerGroupMembership(ctx, server.ID); err != nil {
+			return nil, err
 		}
 	}
+	return server, nil
+}
 
-	err = ex.waitForServerStatus(ctx, server.ID, []string{client.ServerStatusBuild}, []string{client.ServerStatusActive}, 1200)
+// waitForServerActive waits until the server with the specified ID reports "ACTIVE".
+func (ex *Executor) waitForServerActive(ctx context.Context, serverID string) error {
+	err := ex.waitForServerStatus(ctx, serverID, []string{client.ServerStatusBuild}, []string{client.ServerStatusActive}, ex.timeouts().ServerCreate)
 	if err != nil {
-		return "", deleteOnFail(fmt.Errorf("error waiting for server [ID=%q] to reach target status: %w", server.ID, err))
+		return fmt.Errorf("error waiting for server [ID=%q] to reach target status: %w", serverID, err)
+	}
+	return nil
+}
+
+// InitializeMachine performs the post-boot configuration of an "ACTIVE" server. If a providerID is supplied it is used
+// instead of the machineName to locate the server. All steps are idempotent, so that a failed initialization can be
+// retried without recreating the server.
+func (ex *Executor) InitializeMachine(ctx context.Context, machineName, providerID string) (string, error) {
+	server, err := ex.getMachine(ctx, machineName, providerID)
+	if err != nil {
+		return "", err
 	}
 
-	if err := ex.patchServerPortsForPodNetwork(server.ID); err != nil {
-		return "", deleteOnFail(fmt.Errorf("failed to patch server [ID=%q] ports: %s", server.ID, err))
+	switch server.Status {
+	case client.ServerStatusActive:
+	case client.ServerStatusError:
+		return "", fmt.Errorf("server [ID=%q] fault: %+v: %w", server.ID, server.Fault, ErrServerFailed)
+	case client.ServerStatusShutoff:
+		return "", fmt.Errorf("server [ID=%q]: %w", server.ID, ErrServerShutoff)
+	default:
+		return "", fmt.Errorf("server [ID=%q] is in status %q, expected %q", server.ID, server.Status, client.ServerStatusActive)
+	}
+
+	if err := ex.patchServerPortsForPodNetwork(ctx, server.ID); err != nil {
+		return "", fmt.Errorf("failed to patch server [ID=%q] ports: %w", server.ID, err)
+	}
+
+	if err := ex.ensureFloatingIP(ctx, server); err != nil {
+		return "", fmt.Errorf("failed to ensure floating IP for server [ID=%q]: %w", server.ID, err)
+	}
+
+	if err := ex.reconcileServerMetadata(ctx, server); err != nil {
+		return "", fmt.Errorf("failed to reconcile server [ID=%q] metadata: %w", server.ID, err)
+	}
+
+	if err := ex.reconcileServerTags(ctx, server); err != nil {
+		return "", fmt.Errorf("failed to reconcile server [ID=%q] tags: %w", server.ID, err)
 	}
 
 	return encodeProviderID(ex.Config.Spec.Region, server.ID), nil
 }
 
 // resolveServerNetworks resolves the network configuration for the server.
-func (ex *Executor) resolveServerNetworks(ctx context.Context, machineName string) ([]servers.Network, error) {
+func (ex *Executor) resolveServerNetworks(ctx context.Context, machineName string) (_ []servers.Network, err error) {
+	ctx, span := tracing.Start(ctx, "resolveServerNetworks", trace.WithAttributes(tracing.MachineName(machineName)))
+	defer func() { tracing.End(span, err) }()
+
 	var (
 		networkID      = ex.Config.Spec.NetworkID
 		subnetID       = ex.Config.Spec.SubnetID
@@ -120,7 +257,7 @@ func (ex *Executor) resolveServerNetworks(ctx context.Context, machineName strin
 	// If SubnetID is specified in addition to NetworkID, we have to preallocate a Neutron Port to force the VMs to get IP from the subnet's range.
 	if ex.isUserManagedNetwork() {
 		// check if the subnet exists
-		if _, err := ex.Network.GetSubnet(*subnetID); err != nil {
+		if _, err := ex.Network.GetSubnet(ctx, *subnetID); err != nil {
 			return nil, err
 		}
 
@@ -146,7 +283,7 @@ func (ex *Executor) resolveServerNetworks(ctx context.Context, machineName strin
 			err               error
 		)
 		if isEmptyString(ptr.To(network.Id)) {
-			resolvedNetworkID, err = ex.Network.NetworkIDFromName(network.Name)
+			resolvedNetworkID, err = ex.Network.NetworkIDFromName(ctx, network.Name)
 			if err != nil {
 				return nil, err
 			}
@@ -158,16 +295,23 @@ func (ex *Executor) resolveServerNetworks(ctx context.Context, machineName strin
 	return serverNetworks, nil
 }
 
+// timeouts returns the effective timeouts, taking the overrides of the provider spec into account.
+func (ex *Executor) timeouts() Timeouts {
+	return ex.Timeouts.withOverrides(ex.Config.Spec.Timeouts)
+}
+
 // waitForServerStatus blocks until the server with the specified ID reaches one of the target status.
 // waitForServerStatus will fail if an error occurs, the operation it timeouts after the specified time, or the server status is not in the pending list.
-func (ex *Executor) waitForServerStatus(ctx context.Context, serverID string, pending []string, target []string, secs int) error {
-	return wait.PollUntilContextTimeout(
+func (ex *Executor) waitForServerStatus(ctx context.Context, serverID string, pending []string, target []string, timeout time.Duration) (err error) {
+	ctx, span := tracing.Start(ctx, "waitForServerStatus", trace.WithAttributes(attribute.String("server.id", serverID), attribute.StringSlice("server.target_status", target)))
+	defer func() { tracing.End(span, err) }()
+
+	return pollWithBackoff(
 		ctx,
-		10*time.Second,
-		time.Duration(secs)*time.Second,
-		true,
-		func(_ context.Context) (done bool, err error) {
-			current, err := ex.Compute.GetServer(serverID)
+		ex.timeouts().PollInterval,
+		timeout,
+		func(ctx context.Context) (done bool, err error) {
+			current, err := ex.Compute.GetServer(ctx, serverID)
 			if err != nil {
 				if client.IsNotFoundError(err) && strSliceContains(target, client.ServerStatusDeleted) {
 					return true, nil
@@ -195,38 +339,45 @@ func (ex *Executor) waitForServerStatus(ctx context.Context, serverID string, pe
 }
 
 // deployServer handles creating the server instance.
-func (ex *Executor) deployServer(ctx context.Context, machineName string, userData []byte, nws []servers.Network) (*servers.Server, error) {
+func (ex *Executor) deployServer(ctx context.Context, machineName string, userData []byte, nws []servers.Network, p placement, createdVolumes sets.Set[string]) (_ *servers.Server, err error) {
+	ctx, span := tracing.Start(ctx, "deployServer", trace.WithAttributes(tracing.MachineName(machineName), attribute.String("availability_zone", p.availabilityZone), attribute.String("flavor", p.flavorName)))
+	defer func() { tracing.End(span, err) }()
+
 	keyName := ex.Config.Spec.KeyName
 	imageName := ex.Config.Spec.ImageName
 	imageID := ex.Config.Spec.ImageID
 	securityGroups := ex.Config.Spec.SecurityGroups
-	availabilityZone := ex.Config.Spec.AvailabilityZone
-	metadata := ex.Config.Spec.Tags
+	availabilityZone := p.availabilityZone
+	metadata := serverMetadata(ex.Config.Spec.Tags, p)
 	rootDiskSize := ex.Config.Spec.RootDiskSize
 	useConfigDrive := ex.Config.Spec.UseConfigDrive
-	flavorName := ex.Config.Spec.FlavorName
+	flavorName := p.flavorName
 
 	var (
 		imageRef   string
 		createOpts servers.CreateOptsBuilder
-		err        error
 	)
 
-	// use imageID if provided, otherwise try to resolve the imageName to an imageID
+	// use imageID if provided, otherwise try to resolve the imageSelector or the imageName to an imageID
 	if imageID != "" {
 		imageRef = imageID
+	} else if ex.Config.Spec.ImageSelector != nil {
+		imageRef, err = ex.selectImage(ctx, ex.Config.Spec.ImageSelector)
+		if err != nil {
+			return nil, fmt.Errorf("error resolving image ID from image selector: %v", err)
+		}
 	} else {
-		imageRef, err = ex.Compute.ImageIDFromName(imageName)
+		imageRef, err = ex.Compute.ImageIDFromName(ctx, imageName)
 		if err != nil {
 			return nil, fmt.Errorf("error resolving image ID from image name %q: %v", imageName, err)
 		}
 	}
-	flavorRef, err := ex.Compute.FlavorIDFromName(flavorName)
+	flavorRef, err := ex.Compute.FlavorIDFromName(ctx, flavorName)
 	if err != nil {
-		return nil, fmt.Errorf("error resolving flavor ID from flavor name %q: %v", imageName, err)
+		return nil, fmt.Errorf("error resolving flavor ID from flavor name %q: %v", flavorName, err)
 	}
 
-	createOpts = &servers.CreateOpts{
+	serverOpts := &servers.CreateOpts{
 		Name:             machineName,
 		FlavorRef:        flavorRef,
 		ImageRef:         imageRef,
@@ -237,38 +388,83 @@ func (ex *Executor) deployServer(ctx context.Context, machineName string, userDa
 		AvailabilityZone: availabilityZone,
 		ConfigDrive:      useConfigDrive,
 	}
+	// tag the server right away if possible, otherwise the tags are added by InitializeMachine
+	if ex.Compute.Supports(client.FeatureServerCreateTags) {
+		if ownershipTags, ok := ex.ownershipTags(); ok {
+			serverOpts.Tags = ownershipTags
+		}
+	}
+	createOpts = serverOpts
+
+	// clouds without the features derive the hostname from the sanitized server name and leave the description empty
+	details := client.ServerDetailsCreateOptsExt{CreateOptsBuilder: createOpts}
+	if ex.Compute.Supports(client.FeatureServerDescription) {
+		details.Description = fmt.Sprintf("Machine %s managed by the machine-controller-manager", machineName)
+	}
+	if ex.Compute.Supports(client.FeatureServerHostname) && len(validation.IsDNS1123Label(machineName)) == 0 {
+		details.Hostname = machineName
+	}
+	if details.Description != "" || details.Hostname != "" {
+		createOpts = details
+	}
 
 	createOpts = &keypairs.CreateOptsExt{
 		CreateOptsBuilder: createOpts,
 		KeyName:           keyName,
 	}
 
-	if ex.Config.Spec.ServerGroupID != nil {
-		hints := schedulerhints.SchedulerHints{
-			Group: *ex.Config.Spec.ServerGroupID,
+	serverGroupID := ptr.Deref(ex.Config.Spec.ServerGroupID, "")
+	if ex.Config.Spec.ManagedServerGroup != nil {
+		serverGroupID, err = ex.ensureServerGroup(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error ensuring server group: %v", err)
 		}
+	}
+	hints, err := ex.schedulerHints(ctx, serverGroupID)
+	if err != nil {
+		return nil, fmt.Errorf("error resolving scheduler hints: %v", err)
+	}
+	if hints != nil {
 		createOpts = schedulerhints.CreateOptsExt{
 			CreateOptsBuilder: createOpts,
 			SchedulerHints:    hints,
 		}
 	}
 
-	// If a custom block_device (root disk size is provided) we need to boot from volume
-	if rootDiskSize > 0 {
-		return ex.bootFromVolume(ctx, machineName, imageRef, createOpts)
+	// If a custom block_device (root disk size or data volumes are provided) we need to boot from volume
+	if rootDiskSize > 0 || len(ex.Config.Spec.DataVolumes) > 0 {
+		return ex.bootFromVolume(ctx, machineName, imageRef, availabilityZone, createOpts, createdVolumes)
 	}
 
-	return ex.Compute.CreateServer(createOpts)
+	return ex.Compute.CreateServer(ctx, createOpts)
 }
 
-func (ex *Executor) bootFromVolume(ctx context.Context, machineName, imageID string, createOpts servers.CreateOptsBuilder) (*servers.Server, error) {
+func (ex *Executor) bootFromVolume(ctx context.Context, machineName, imageID, availabilityZone string, createOpts servers.CreateOptsBuilder, createdVolumes sets.Set[string]) (*servers.Server, error) {
 	blockDeviceOpts := make([]bootfromvolume.BlockDevice, 1)
+	multiattach := false
 
-	if ex.Config.Spec.RootDiskType != nil {
-		volumeID, err := ex.ensureVolume(ctx, machineName, imageID)
+	if ex.Config.Spec.RootDiskSize <= 0 {
+		// the server only boots from volume because of its data volumes, the root disk stays an ephemeral disk
+		blockDeviceOpts[0] = bootfromvolume.BlockDevice{
+			UUID:                imageID,
+			BootIndex:           0,
+			DeleteOnTermination: true,
+			SourceType:          "image",
+			DestinationType:     "local",
+		}
+	} else if ex.Config.Spec.RootDiskType != nil {
+		volumeID, rootMultiattach, _, err := ex.ensureVolume(ctx, volumes.CreateOpts{
+			Name:             machineName,
+			VolumeType:       *ex.Config.Spec.RootDiskType,
+			Size:             ex.Config.Spec.RootDiskSize,
+			ImageID:          imageID,
+			AvailabilityZone: availabilityZone,
+			Metadata:         ex.Config.Spec.Tags,
+		})
 		if err != nil {
 			return nil, fmt.Errorf("failed to ensure volume [Name=%q]: %s", machineName, err)
 		}
+		multiattach = rootMultiattach
 
 		blockDeviceOpts[0] = bootfromvolume.BlockDevice{
 			UUID:                volumeID,
@@ -289,63 +485,129 @@ func (ex *Executor) bootFromVolume(ctx context.Context, machineName, imageID str
 		}
 	}
 
+	dataVolumeOpts, dataMultiattach, err := ex.ensureDataVolumes(ctx, machineName, availabilityZone, createdVolumes)
+	if err != nil {
+		return nil, err
+	}
+	blockDeviceOpts = append(blockDeviceOpts, dataVolumeOpts...)
+
 	klog.V(3).Infof("[DEBUG] Block Device Options: %+v", blockDeviceOpts)
 	createOpts = &bootfromvolume.CreateOptsExt{
 		CreateOptsBuilder: createOpts,
 		BlockDevice:       blockDeviceOpts,
 	}
-	return ex.Compute.BootFromVolume(createOpts)
+	if multiattach || dataMultiattach {
+		createOpts = client.MultiattachCreateOptsExt{CreateOptsBuilder: createOpts}
+	}
+	return ex.Compute.BootFromVolume(ctx, createOpts)
 }
 
-func (ex *Executor) ensureVolume(ctx context.Context, name, imageID string) (string, error) {
-	var (
-		volumeID string
-		err      error
-	)
+// ensureDataVolumes creates the data volumes of the machine if they do not exist yet and returns the block device mappings
+// to attach them to the server, as well as whether any of them can be attached to multiple servers. The volumes are never
+// deleted by Nova, instead DeleteMachine deletes them by name, so that volumes of a partially created machine are cleaned
+// up as well. The names of the created volumes which are kept on termination are added to createdVolumes.
+func (ex *Executor) ensureDataVolumes(ctx context.Context, machineName, availabilityZone string, createdVolumes sets.Set[string]) ([]bootfromvolume.BlockDevice, bool, error) {
+	blockDeviceOpts := make([]bootfromvolume.BlockDevice, 0, len(ex.Config.Spec.DataVolumes))
+	anyMultiattach := false
+
+	for _, dataVolume := range ex.Config.Spec.DataVolumes {
+		name := dataVolumeName(machineName, dataVolume.Name)
+		volumeID, multiattach, created, err := ex.ensureVolume(ctx, volumes.CreateOpts{
+			Name:             name,
+			VolumeType:       ptr.Deref(dataVolume.Type, ""),
+			Size:             dataVolume.Size,
+			AvailabilityZone: availabilityZone,
+			Metadata:         ex.Config.Spec.Tags,
+		})
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to ensure data volume [Name=%q]: %w", name, err)
+		}
+		anyMultiattach = anyMultiattach || multiattach
+		if created && !ptr.Deref(dataVolume.DeleteOnTermination, true) {
+			createdVolumes.Insert(name)
+		}
 
-	volumeID, err = ex.Storage.VolumeIDFromName(name)
+		blockDeviceOpts = append(blockDeviceOpts, bootfromvolume.BlockDevice{
+			UUID:                volumeID,
+			BootIndex:           -1,
+			DeleteOnTermination: false,
+			SourceType:          "volume",
+			DestinationType:     "volume",
+		})
+	}
+
+	return blockDeviceOpts, anyMultiattach, nil
+}
+
+// ensureVolume creates a volume with the given options if no volume with the same name exists yet and waits until the
+// volume is available. It returns the ID of the volume, whether it can be attached to multiple servers and whether it was
+// created.
+func (ex *Executor) ensureVolume(ctx context.Context, opts volumes.CreateOpts) (_ string, multiattach, created bool, err error) {
+	ctx, span := tracing.Start(ctx, "ensureVolume", trace.WithAttributes(attribute.String("volume.name", opts.Name)))
+	defer func() { tracing.End(span, err) }()
+
+	volumeID, err := ex.Storage.VolumeIDFromName(ctx, opts.Name)
 	if err != nil && !client.IsNotFoundError(err) {
-		return "", err
+		return "", false, false, err
+	}
+	exists := err == nil
+
+	// a volume left behind by a creation attempt in another availability zone cannot be attached, recreate it
+	if exists && opts.AvailabilityZone != "" {
+		volume, err := ex.Storage.GetVolume(ctx, volumeID)
+		switch {
+		case client.IsNotFoundError(err):
+			exists = false
+		case err != nil:
+			return "", false, false, err
+		case volume.AvailabilityZone != opts.AvailabilityZone:
+			klog.Infof("recreating volume [Name=%q, ID=%q] in availability zone %q, since it is in availability zone %q", opts.Name, volumeID, opts.AvailabilityZone, volume.AvailabilityZone)
+			if err := ex.deleteVolume(ctx, opts.Name); err != nil {
+				return "", false, false, err
+			}
+			exists = false
+		}
 	}
 
-	if client.IsNotFoundError(err) {
-		volume, err := ex.Storage.CreateVolume(volumes.CreateOpts{
-			Name:             name,
-			VolumeType:       *ex.Config.Spec.RootDiskType,
-			Size:             ex.Config.Spec.RootDiskSize,
-			ImageID:          imageID,
-			AvailabilityZone: ex.Config.Spec.AvailabilityZone,
-			Metadata:         ex.Config.Spec.Tags,
-		})
+	if !exists {
+		volume, err := ex.Storage.CreateVolume(ctx, opts)
 		if err != nil {
-			return "", fmt.Errorf("failed to created volume [Name=%s]: %v", name, err)
+			return "", false, false, fmt.Errorf("failed to created volume [Name=%s]: %v", opts.Name, err)
 		}
 		volumeID = volume.ID
 	}
 
 	pendingStatuses := []string{client.VolumeStatusCreating, client.VolumeStatusDownloading}
 	targetStatuses := []string{client.VolumeStatusAvailable}
-	if err := ex.waitForVolumeStatus(ctx, volumeID, pendingStatuses, targetStatuses, 1200); err != nil {
-		return "", err
+	volume, err := ex.waitForVolumeStatus(ctx, volumeID, pendingStatuses, targetStatuses, ex.timeouts().VolumeCreate)
+	if err != nil {
+		return "", false, false, err
+	}
+	if volume == nil {
+		return "", false, false, fmt.Errorf("volume [Name=%q, ID=%q] was deleted during its creation", opts.Name, volumeID)
 	}
 
-	return volumeID, nil
+	return volumeID, volume.Multiattach, !exists, nil
 }
 
-func (ex *Executor) waitForVolumeStatus(ctx context.Context, volumeID string, pending, target []string, secs int) error {
-	return wait.PollUntilContextTimeout(
+// waitForVolumeStatus blocks until the volume with the specified ID reaches one of the target status or is not found
+// anymore. It returns the volume in the target status, or nil if the volume is not found.
+func (ex *Executor) waitForVolumeStatus(ctx context.Context, volumeID string, pending, target []string, timeout time.Duration) (*volumes.Volume, error) {
+	var volume *volumes.Volume
+	err := pollWithBackoff(
 		ctx,
-		10*time.Second,
-		time.Duration(secs)*time.Second,
-		true,
-		func(_ context.Context) (done bool, err error) {
-			current, err := ex.Storage.GetVolume(volumeID)
+		ex.timeouts().PollInterval,
+		timeout,
+		func(ctx context.Context) (done bool, err error) {
+			current, err := ex.Storage.GetVolume(ctx, volumeID)
 			if err != nil {
 				if client.IsNotFoundError(err) {
+					volume = nil
 					return true, nil
 				}
 				return false, err
 			}
+			volume = current
 
 			klog.V(3).Infof("waiting for volume[ID=%q] with current status %v, to reach status %v.", volumeID, current.Status, target)
 			if strSliceContains(target, current.Status) {
@@ -363,24 +625,49 @@ func (ex *Executor) waitForVolumeStatus(ctx context.Context, volumeID string, pe
 
 			return false, retErr
 		})
+	if err != nil {
+		return nil, err
+	}
+	return volume, nil
 }
 
 // patchServerPortsForPodNetwork updates a server's ports with rules for whitelisting the pod network CIDR.
-func (ex *Executor) patchServerPortsForPodNetwork(serverID string) error {
-	allPorts, err := ex.Network.ListPorts(&ports.ListOpts{
+func (ex *Executor) patchServerPortsForPodNetwork(ctx context.Context, serverID string) (err error) {
+	ctx, span := tracing.Start(ctx, "patchServerPortsForPodNetwork", trace.WithAttributes(attribute.String("server.id", serverID)))
+	defer func() { tracing.End(span, err) }()
+
+	missingPairs, err := ex.missingPodNetworkAddressPairs(ctx, serverID)
+	if err != nil {
+		return err
+	}
+
+	for portID, pairs := range missingPairs {
+		if err := ex.Network.UpdatePort(ctx, portID, ports.UpdateOpts{
+			AllowedAddressPairs: &pairs,
+		}); err != nil {
+			return fmt.Errorf("failed to update allowed address pair for port [ID=%q]: %v", portID, err)
+		}
+	}
+	return nil
+}
+
+// missingPodNetworkAddressPairs returns the allowed address pairs for every server port in the pod network, which does
+// not yet allow all pod network CIDRs. The returned pairs contain the port's existing pairs as well as the missing ones.
+func (ex *Executor) missingPodNetworkAddressPairs(ctx context.Context, serverID string) (map[string][]ports.AddressPair, error) {
+	allPorts, err := ex.Network.ListPorts(ctx, &ports.ListOpts{
 		DeviceID: serverID,
 	})
 	if err != nil {
-		return fmt.Errorf("failed to get ports: %v", err)
+		return nil, fmt.Errorf("failed to get ports: %v", err)
 	}
 
 	if len(allPorts) == 0 {
-		return fmt.Errorf("got an empty port list for server %q", serverID)
+		return nil, fmt.Errorf("got an empty port list for server %q", serverID)
 	}
 
-	podNetworkIDs, err := ex.resolveNetworkIDsForPodNetwork()
+	podNetworkIDs, err := ex.resolveNetworkIDsForPodNetwork(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to resolve network IDs for the pod network %v", err)
+		return nil, fmt.Errorf("failed to resolve network IDs for the pod network %v", err)
 	}
 
 	// coalesce all pod network CIDRs into a single slice.
@@ -389,36 +676,230 @@ func (ex *Executor) patchServerPortsForPodNetwork(serverID string) error {
 		podCIDRs.Insert(ex.Config.Spec.PodNetworkCidr)
 	}
 
+	result := map[string][]ports.AddressPair{}
 	for _, port := range allPorts {
 		// if the port is not part of the networks we care about, continue.
 		if !podNetworkIDs.Has(port.NetworkID) {
 			continue
 		}
 
-		for _, cidr := range podCIDRs.List() {
-			if err := func() error {
-				for _, pair := range port.AllowedAddressPairs {
-					if pair.IPAddress == cidr {
-						klog.V(3).Infof("port [ID=%q] already allows pod network CIDR range. Skipping update...", port.ID)
-						return nil
-					}
-				}
-				if err := ex.Network.UpdatePort(port.ID, ports.UpdateOpts{
-					AllowedAddressPairs: &[]ports.AddressPair{{IPAddress: cidr}},
-				}); err != nil {
-					return fmt.Errorf("failed to update allowed address pair for port [ID=%q]: %v", port.ID, err)
-				}
-				return nil
-			}(); err != nil {
-				return err
+		allowed := sets.NewString()
+		for _, pair := range port.AllowedAddressPairs {
+			allowed.Insert(pair.IPAddress)
+		}
+
+		missing := podCIDRs.Difference(allowed)
+		if missing.Len() == 0 {
+			klog.V(3).Infof("port [ID=%q] already allows pod network CIDR range. Skipping update...", port.ID)
+			continue
+		}
+
+		pairs := make([]ports.AddressPair, 0, len(port.AllowedAddressPairs)+missing.Len())
+		pairs = append(pairs, port.AllowedAddressPairs...)
+		for _, cidr := range missing.List() {
+			pairs = append(pairs, ports.AddressPair{IPAddress: cidr})
+		}
+		result[port.ID] = pairs
+	}
+	return result, nil
+}
+
+// reconcileServerMetadata ensures that the server's metadata contains all tags of the machine class.
+func (ex *Executor) reconcileServerMetadata(ctx context.Context, server *servers.Server) error {
+	missing := missingServerMetadata(server, ex.Config.Spec.Tags)
+	if len(missing) == 0 {
+		return nil
+	}
+
+	klog.V(3).Infof("updating metadata of server [ID=%q]", server.ID)
+	return ex.Compute.UpdateServerMetadata(ctx, server.ID, servers.MetadataOpts(missing))
+}
+
+// ownershipTags returns the cluster and role markers as server tags. It returns false if the compute API does not
+// support server tags or the markers cannot be represented as server tags, in which case ownership is only recorded in
+// the server's metadata.
+func (ex *Executor) ownershipTags() ([]string, bool) {
+	if !ex.Compute.Supports(client.FeatureServerTags) {
+		return nil, false
+	}
+	searchClusterName, searchNodeRole, ok := findMandatoryTags(ex.Config.Spec.Tags)
+	if !ok || !isValidServerTag(searchClusterName) || !isValidServerTag(searchNodeRole) {
+		return nil, false
+	}
+	return []string{searchClusterName, searchNodeRole}, true
+}
+
+// reconcileServerTags adds the cluster and role markers as server tags, if they are missing and server tags are
+// supported.
+func (ex *Executor) reconcileServerTags(ctx context.Context, server *servers.Server) error {
+	ownershipTags, ok := ex.ownershipTags()
+	if !ok {
+		return nil
+	}
+	missing := missingServerTags(server, ownershipTags)
+	if len(missing) == 0 {
+		return nil
+	}
+
+	klog.V(3).Infof("adding tags %v to server [ID=%q]", missing, server.ID)
+	return ex.Compute.AddServerTags(ctx, server.ID, missing)
+}
+
+// isServerInitialized returns true if all steps of InitializeMachine have been performed on the server.
+func (ex *Executor) isServerInitialized(ctx context.Context, server *servers.Server) (bool, error) {
+	if len(missingServerMetadata(server, ex.Config.Spec.Tags)) > 0 {
+		return false, nil
+	}
+
+	missingPairs, err := ex.missingPodNetworkAddressPairs(ctx, server.ID)
+	if err != nil {
+		return false, err
+	}
+	if len(missingPairs) > 0 {
+		return false, nil
+	}
+
+	if ex.Config.Spec.FloatingIP != nil {
+		_, fip, err := ex.getFloatingIPForServer(ctx, server.ID)
+		if err != nil {
+			return false, err
+		}
+		return fip != nil, nil
+	}
+	return true, nil
+}
+
+// ensureFloatingIP associates a floating IP with the server's port in the primary network, if the machine class requests
+// a floating IP. Depending on the configuration, a tagged but unassociated floating IP is reused, otherwise a new one is
+// allocated.
+func (ex *Executor) ensureFloatingIP(ctx context.Context, server *servers.Server) error {
+	fipConfig := ex.Config.Spec.FloatingIP
+	if fipConfig == nil {
+		return nil
+	}
+
+	searchClusterName, searchNodeRole, ok := findMandatoryTags(ex.Config.Spec.Tags)
+	if !ok {
+		klog.Warningf("operation can not proceed: cluster/role tags are missing")
+		return fmt.Errorf("operation can not proceed: cluster/role tags are missing")
+	}
+	fipTags := []string{searchClusterName, searchNodeRole}
+
+	port, fip, err := ex.getFloatingIPForServer(ctx, server.ID)
+	if err != nil {
+		return err
+	}
+	if fip != nil {
+		klog.V(3).Infof("port [ID=%q] is already associated with floating IP [ID=%q]", port.ID, fip.ID)
+		if !sets.New(fip.Tags...).HasAll(fipTags...) {
+			return ex.Network.TagFloatingIP(ctx, fip.ID, fipTags)
+		}
+		return nil
+	}
+
+	networkID := fipConfig.NetworkID
+	if isEmptyString(ptr.To(networkID)) {
+		networkID, err = ex.Network.NetworkIDFromName(ctx, fipConfig.PoolName)
+		if err != nil {
+			return fmt.Errorf("failed to resolve floating IP pool %q: %w", fipConfig.PoolName, err)
+		}
+	}
+
+	if fipConfig.ReuseUnassociated {
+		candidates, err := ex.Network.ListFloatingIPs(ctx, floatingips.ListOpts{
+			FloatingNetworkID: networkID,
+			Tags:              strings.Join(fipTags, ","),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to list floating IPs: %w", err)
+		}
+		for _, candidate := range candidates {
+			if candidate.PortID != "" {
+				continue
 			}
+			klog.V(3).Infof("associating existing floating IP [ID=%q] with port [ID=%q]", candidate.ID, port.ID)
+			return ex.Network.UpdateFloatingIP(ctx, candidate.ID, floatingips.UpdateOpts{
+				PortID:      ptr.To(port.ID),
+				Description: ptr.To(server.Name),
+			})
+		}
+	}
+
+	opts := floatingips.CreateOpts{
+		FloatingNetworkID: networkID,
+		PortID:            port.ID,
+		Description:       server.Name,
+	}
+	if fipConfig.SubnetID != nil {
+		opts.SubnetID = *fipConfig.SubnetID
+	}
+
+	klog.V(3).Infof("allocating floating IP for port [ID=%q]", port.ID)
+	fip, err = ex.Network.CreateFloatingIP(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("failed to allocate floating IP: %w", err)
+	}
+	// the floating IP is released by its tags, so an untagged one would leak once the machine is deleted.
+	if err := ex.Network.TagFloatingIP(ctx, fip.ID, fipTags); err != nil {
+		klog.Errorf("failed to tag floating IP [ID=%q], deleting it: %s", fip.ID, err)
+		if deleteErr := ex.Network.DeleteFloatingIP(ctx, fip.ID); deleteErr != nil && !client.IsNotFoundError(deleteErr) {
+			return fmt.Errorf("failed to tag floating IP [ID=%q]: %w, failed to delete it: %v", fip.ID, err, deleteErr)
 		}
+		return fmt.Errorf("failed to tag floating IP [ID=%q]: %w", fip.ID, err)
 	}
 	return nil
 }
 
+// getFloatingIPForServer returns the server's port in the primary network and the floating IP associated with it. If
+// there is no associated floating IP, nil is returned instead.
+func (ex *Executor) getFloatingIPForServer(ctx context.Context, serverID string) (*ports.Port, *floatingips.FloatingIP, error) {
+	primaryNetworkID, err := ex.resolvePrimaryNetworkID(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to resolve the primary network: %w", err)
+	}
+
+	serverPorts, err := ex.Network.ListPorts(ctx, &ports.ListOpts{
+		DeviceID:  serverID,
+		NetworkID: primaryNetworkID,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get ports: %w", err)
+	}
+	if len(serverPorts) == 0 {
+		return nil, nil, fmt.Errorf("server %q has no port in network [ID=%q]", serverID, primaryNetworkID)
+	}
+	port := &serverPorts[0]
+
+	fips, err := ex.Network.ListFloatingIPs(ctx, floatingips.ListOpts{
+		PortID: port.ID,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list floating IPs: %w", err)
+	}
+	if len(fips) == 0 {
+		return port, nil, nil
+	}
+	return port, &fips[0], nil
+}
+
+// resolvePrimaryNetworkID resolves the ID of the first network the server is attached to.
+func (ex *Executor) resolvePrimaryNetworkID(ctx context.Context) (string, error) {
+	if !isEmptyString(ptr.To(ex.Config.Spec.NetworkID)) {
+		return ex.Config.Spec.NetworkID, nil
+	}
+	if len(ex.Config.Spec.Networks) == 0 {
+		return "", fmt.Errorf("no network configured")
+	}
+
+	network := ex.Config.Spec.Networks[0]
+	if !isEmptyString(ptr.To(network.Id)) {
+		return network.Id, nil
+	}
+	return ex.Network.NetworkIDFromName(ctx, network.Name)
+}
+
 // resolveNetworkIDsForPodNetwork resolves the networks that accept traffic from the pod CIDR range.
-func (ex *Executor) resolveNetworkIDsForPodNetwork() (sets.Set[string], error) {
+func (ex *Executor) resolveNetworkIDsForPodNetwork(ctx context.Context) (sets.Set[string], error) {
 	var (
 		networkID     = ex.Config.Spec.NetworkID
 		networks      = ex.Config.Spec.Networks
@@ -436,7 +917,7 @@ func (ex *Executor) resolveNetworkIDsForPodNetwork() (sets.Set[string], error) {
 			err               error
 		)
 		if isEmptyString(ptr.To(network.Id)) {
-			resolvedNetworkID, err = ex.Network.NetworkIDFromName(network.Name)
+			resolvedNetworkID, err = ex.Network.NetworkIDFromName(ctx, network.Name)
 			if err != nil {
 				return nil, err
 			}
@@ -453,31 +934,26 @@ func (ex *Executor) resolveNetworkIDsForPodNetwork() (sets.Set[string], error) {
 // DeleteMachine deletes a server based on the supplied machineName. If a providerID is supplied it is used instead of the
 // machineName to locate the server.
 func (ex *Executor) DeleteMachine(ctx context.Context, machineName, providerID string) error {
-	var (
-		server *servers.Server
-		err    error
-	)
-
-	if !isEmptyString(ptr.To(providerID)) {
-		serverID := decodeProviderID(providerID)
-		server, err = ex.getMachineByID(ctx, serverID)
-	} else {
-		server, err = ex.getMachineByName(ctx, machineName)
-	}
-
+	server, err := ex.getMachine(ctx, machineName, providerID)
 	if err == nil {
 		klog.V(1).Infof("deleting server [Name=%s, ID=%s]", server.Name, server.ID)
-		if err := ex.Compute.DeleteServer(server.ID); err != nil {
+		if err := ex.Compute.DeleteServer(ctx, server.ID); err != nil {
 			return err
 		}
 
-		if err = ex.waitForServerStatus(ctx, server.ID, nil, []string{client.ServerStatusDeleted}, 1200); err != nil {
-			return fmt.Errorf("error while waiting for server [ID=%q] to be deleted: %v", server.ID, err)
+		if err = ex.waitForServerStatus(ctx, server.ID, nil, []string{client.ServerStatusDeleted}, ex.timeouts().Delete); err != nil {
+			return fmt.Errorf("error while waiting for server [ID=%q] to be deleted: %w", server.ID, err)
 		}
 	} else if !errors.Is(err, ErrNotFound) {
 		return err
 	}
 
+	if ex.Config.Spec.FloatingIP != nil {
+		if err := ex.releaseFloatingIPs(ctx, machineName); err != nil {
+			return err
+		}
+	}
+
 	if ex.isUserManagedNetwork() {
 		err := ex.deletePort(ctx, machineName)
 		if err != nil {
@@ -486,19 +962,64 @@ func (ex *Executor) DeleteMachine(ctx context.Context, machineName, providerID s
 	}
 
 	if ex.Config.Spec.RootDiskType != nil {
-		return ex.deleteVolume(ctx, machineName)
+		if err := ex.deleteVolume(ctx, machineName); err != nil {
+			return err
+		}
 	}
 
+	if err := ex.deleteDataVolumes(ctx, machineName); err != nil {
+		return err
+	}
+
+	if ex.Config.Spec.ManagedServerGroup != nil {
+		return ex.deleteServerGroupIfEmpty(ctx)
+	}
 	return nil
 }
 
-func (ex *Executor) getOrCreatePort(_ context.Context, machineName string) (string, error) {
+// GetMachineStatus returns the provider ID of the server backing the machine. If a providerID is supplied it is used
+// instead of the machineName to locate the server. Servers in ERROR or SHUTOFF status are reported as errors. If the
+// server has not been initialized yet, the provider ID is returned together with an ErrNotInitialized error.
+func (ex *Executor) GetMachineStatus(ctx context.Context, machineName, providerID string) (string, error) {
+	server, err := ex.getMachine(ctx, machineName, providerID)
+	if err != nil {
+		return "", err
+	}
+
+	// servers created before server tags were supported are only marked by their metadata, tag them so that they are
+	// found by the server-side filtering in listServers
+	if err := ex.reconcileServerTags(ctx, server); err != nil {
+		klog.Warningf("failed to reconcile tags of server [ID=%q]: %v", server.ID, err)
+	}
+
+	providerID = encodeProviderID(ex.Config.Spec.Region, server.ID)
+	switch server.Status {
+	case client.ServerStatusError:
+		return "", fmt.Errorf("server [Name=%q, ID=%q] fault: %+v: %w", server.Name, server.ID, server.Fault, ErrServerFailed)
+	case client.ServerStatusShutoff:
+		return "", fmt.Errorf("server [Name=%q, ID=%q]: %w", server.Name, server.ID, ErrServerShutoff)
+	case client.ServerStatusBuild:
+		return providerID, fmt.Errorf("server [Name=%q, ID=%q] is still building: %w", server.Name, server.ID, ErrNotInitialized)
+	case client.ServerStatusActive:
+		initialized, err := ex.isServerInitialized(ctx, server)
+		if err != nil {
+			return "", err
+		}
+		if !initialized {
+			return providerID, fmt.Errorf("server [Name=%q, ID=%q]: %w", server.Name, server.ID, ErrNotInitialized)
+		}
+	}
+
+	return providerID, nil
+}
+
+func (ex *Executor) getOrCreatePort(ctx context.Context, machineName string) (string, error) {
 	var (
 		err              error
 		securityGroupIDs []string
 	)
 
-	portID, err := ex.Network.PortIDFromName(machineName)
+	portID, err := ex.Network.PortIDFromName(ctx, machineName)
 	if err == nil {
 		klog.V(2).Infof("found port [Name=%q, ID=%q]... skipping creation", machineName, portID)
 		return portID, nil
@@ -513,14 +1034,14 @@ func (ex *Executor) getOrCreatePort(_ context.Context, machineName string) (stri
 	klog.V(3).Infof("creating port [Name=%q]... ", machineName)
 
 	for _, securityGroup := range ex.Config.Spec.SecurityGroups {
-		securityGroupID, err := ex.Network.GroupIDFromName(securityGroup)
+		securityGroupID, err := ex.Network.GroupIDFromName(ctx, securityGroup)
 		if err != nil {
 			return "", err
 		}
 		securityGroupIDs = append(securityGroupIDs, securityGroupID)
 	}
 
-	port, err := ex.Network.CreatePort(&ports.CreateOpts{
+	port, err := ex.Network.CreatePort(ctx, &ports.CreateOpts{
 		Name:           machineName,
 		NetworkID:      ex.Config.Spec.NetworkID,
 		FixedIPs:       []ports.IP{{SubnetID: *ex.Config.Spec.SubnetID}},
@@ -537,7 +1058,7 @@ func (ex *Executor) getOrCreatePort(_ context.Context, machineName string) (stri
 	}
 
 	portTags := []string{searchClusterName, searchNodeRole}
-	if err := ex.Network.TagPort(port.ID, portTags); err != nil {
+	if err := ex.Network.TagPort(ctx, port.ID, portTags); err != nil {
 		return "", err
 	}
 
@@ -545,8 +1066,8 @@ func (ex *Executor) getOrCreatePort(_ context.Context, machineName string) (stri
 	return port.ID, nil
 }
 
-func (ex *Executor) deletePort(_ context.Context, machineName string) error {
-	portList, err := ex.Network.ListPorts(ports.ListOpts{
+func (ex *Executor) deletePort(ctx context.Context, machineName string) error {
+	portList, err := ex.Network.ListPorts(ctx, ports.ListOpts{
 		Name: machineName,
 	})
 	if err != nil {
@@ -560,7 +1081,7 @@ func (ex *Executor) deletePort(_ context.Context, machineName string) error {
 	klog.V(2).Infof("deleting ports for machine [Name=%q]", machineName)
 	for _, p := range portList {
 		klog.V(2).Infof("deleting port [ID=%q]", p.ID)
-		err = ex.Network.DeletePort(p.ID)
+		err = ex.Network.DeletePort(ctx, p.ID)
 		if err != nil {
 			klog.Errorf("failed to delete port [ID=%q]: %s", p.ID, err)
 			return err
@@ -571,28 +1092,104 @@ func (ex *Executor) deletePort(_ context.Context, machineName string) error {
 	return nil
 }
 
-func (ex *Executor) deleteVolume(_ context.Context, machineName string) error {
-	volumeID, err := ex.Storage.VolumeIDFromName(machineName)
+// releaseFloatingIPs releases the floating IPs allocated for the machine. Reusable floating IPs are only disassociated,
+// so that they can be picked up by another machine.
+func (ex *Executor) releaseFloatingIPs(ctx context.Context, machineName string) error {
+	searchClusterName, searchNodeRole, ok := findMandatoryTags(ex.Config.Spec.Tags)
+	if !ok {
+		klog.Warningf("operation can not proceed: cluster/role tags are missing")
+		return fmt.Errorf("operation can not proceed: cluster/role tags are missing")
+	}
+
+	fips, err := ex.Network.ListFloatingIPs(ctx, floatingips.ListOpts{
+		Description: machineName,
+		Tags:        strings.Join([]string{searchClusterName, searchNodeRole}, ","),
+	})
+	if err != nil {
+		return fmt.Errorf("error releasing floating IPs for machine [Name=%q]: %s", machineName, err)
+	}
+
+	for _, fip := range fips {
+		if ex.Config.Spec.FloatingIP.ReuseUnassociated {
+			klog.V(2).Infof("disassociating floating IP [ID=%q]", fip.ID)
+			err = ex.Network.UpdateFloatingIP(ctx, fip.ID, floatingips.UpdateOpts{
+				PortID:      ptr.To(""),
+				Description: ptr.To(""),
+			})
+			if err != nil && !client.IsNotFoundError(err) {
+				klog.Errorf("failed to disassociate floating IP [ID=%q]: %s", fip.ID, err)
+				return err
+			}
+			continue
+		}
+
+		klog.V(2).Infof("deleting floating IP [ID=%q]", fip.ID)
+		if err := ex.Network.DeleteFloatingIP(ctx, fip.ID); err != nil {
+			klog.Errorf("failed to delete floating IP [ID=%q]: %s", fip.ID, err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deleteVolume deletes the volume with the given name if it exists. A volume that is still detaching from the deleted
+// server is awaited before the deletion. The deletion is awaited as well, so that a volume with the same name can be
+// created right away, e.g. when the creation is retried in a fallback availability zone.
+func (ex *Executor) deleteVolume(ctx context.Context, name string) error {
+	volumeID, err := ex.Storage.VolumeIDFromName(ctx, name)
 	if err != nil {
 		if client.IsNotFoundError(err) {
 			return nil
 		}
-		return fmt.Errorf("error deleting [Name=%q]: %s", machineName, err)
+		return fmt.Errorf("error deleting volume [Name=%q]: %w", name, err)
 	}
 
-	klog.V(2).Infof("deleting volume [Name=%q]", machineName)
-	err = ex.Storage.DeleteVolume(volumeID)
-	if err != nil {
-		klog.Errorf("failed to delete port [Name=%q]", machineName)
-		return err
+	pendingStatuses := []string{client.VolumeStatusInUse, client.VolumeStatusDetaching}
+	targetStatuses := []string{client.VolumeStatusAvailable, client.VolumeStatusError}
+	if _, err := ex.waitForVolumeStatus(ctx, volumeID, pendingStatuses, targetStatuses, ex.timeouts().Delete); err != nil {
+		return fmt.Errorf("error while waiting for volume [ID=%q] to be detached: %w", volumeID, err)
+	}
+
+	klog.V(2).Infof("deleting volume [Name=%q, ID=%q]", name, volumeID)
+	if err := ex.Storage.DeleteVolume(ctx, volumeID); err != nil && !client.IsNotFoundError(err) {
+		return fmt.Errorf("failed to delete volume [Name=%q]: %w", name, err)
 	}
+
+	// the volume is gone once it is not found anymore
+	if _, err := ex.waitForVolumeStatus(ctx, volumeID, []string{client.VolumeStatusDeleting}, nil, ex.timeouts().Delete); err != nil {
+		return fmt.Errorf("error while waiting for volume [ID=%q] to be deleted: %w", volumeID, err)
+	}
+	return nil
+}
+
+// deleteDataVolumes deletes the data volumes of the machine which should be deleted on termination.
+func (ex *Executor) deleteDataVolumes(ctx context.Context, machineName string) error {
+	for _, dataVolume := range ex.Config.Spec.DataVolumes {
+		if !ptr.Deref(dataVolume.DeleteOnTermination, true) {
+			continue
+		}
+		if err := ex.deleteVolume(ctx, dataVolumeName(machineName, dataVolume.Name)); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// getMachine fetches the data for a server based on a provider-encoded ID. If providerID is empty, the server is located
+// by machineName instead.
+func (ex *Executor) getMachine(ctx context.Context, machineName, providerID string) (*servers.Server, error) {
+	if !isEmptyString(ptr.To(providerID)) {
+		return ex.getMachineByID(ctx, decodeProviderID(providerID))
+	}
+	return ex.getMachineByName(ctx, machineName)
+}
+
 // getMachineByProviderID fetches the data for a server based on a provider-encoded ID.
-func (ex *Executor) getMachineByID(_ context.Context, serverID string) (*servers.Server, error) {
+func (ex *Executor) getMachineByID(ctx context.Context, serverID string) (*servers.Server, error) {
 	klog.V(2).Infof("finding server with [ID=%q]", serverID)
-	server, err := ex.Compute.GetServer(serverID)
+	server, err := ex.Compute.GetServer(ctx, serverID)
 	if err != nil {
 		klog.V(2).Infof("error finding server [ID=%q]: %v", serverID, err)
 		if client.IsNotFoundError(err) {
@@ -608,10 +1205,8 @@ func (ex *Executor) getMachineByID(_ context.Context, serverID string) (*servers
 		return nil, fmt.Errorf("operation can not proceed: cluster/role tags are missing")
 	}
 
-	if _, nameOk := server.Metadata[searchClusterName]; nameOk {
-		if _, roleOk := server.Metadata[searchNodeRole]; roleOk {
-			return server, nil
-		}
+	if hasOwnershipMarkers(server, searchClusterName, searchNodeRole) {
+		return server, nil
 	}
 
 	klog.Warningf("server [ID=%q] found, but cluster/role tags are missing/not matching", serverID)
@@ -621,17 +1216,17 @@ func (ex *Executor) getMachineByID(_ context.Context, serverID string) (*servers
 // getMachineByName returns a server that matches the following criteria:
 // a) has the same name as machineName
 // b) has the cluster and role tags as set in the machineClass
-// The current approach is weak because the tags are currently stored as server metadata. Later Nova versions allow
-// to store tags in a respective field and do a server-side filtering. To avoid incompatibility with older versions
-// we will continue making the filtering clientside.
-func (ex *Executor) getMachineByName(_ context.Context, machineName string) (*servers.Server, error) {
+// The tags are matched against the server metadata and, on clouds supporting them, the server tags. The servers are
+// already filtered server-side by name, so the ownership check is done client-side to recognize servers with either
+// kind of marker.
+func (ex *Executor) getMachineByName(ctx context.Context, machineName string) (*servers.Server, error) {
 	searchClusterName, searchNodeRole, ok := findMandatoryTags(ex.Config.Spec.Tags)
 	if !ok {
 		klog.Warningf("getMachineByName operation can not proceed: cluster/role tags are missing for machine [Name=%q]", machineName)
 		return nil, fmt.Errorf("getMachineByName operation can not proceed: cluster/role tags are missing for machine [Name=%q]", machineName)
 	}
 
-	listedServers, err := ex.Compute.ListServers(&servers.ListOpts{
+	listedServers, err := ex.Compute.ListServers(ctx, &servers.ListOpts{
 		Name: machineName,
 	})
 	if err != nil {
@@ -640,12 +1235,8 @@ func (ex *Executor) getMachineByName(_ context.Context, machineName string) (*se
 
 	var matchingServers []servers.Server
 	for _, server := range listedServers {
-		if server.Name == machineName {
-			if _, nameOk := server.Metadata[searchClusterName]; nameOk {
-				if _, roleOk := server.Metadata[searchNodeRole]; roleOk {
-					matchingServers = append(matchingServers, server)
-				}
-			}
+		if server.Name == machineName && hasOwnershipMarkers(&server, searchClusterName, searchNodeRole) {
+			matchingServers = append(matchingServers, server)
 		}
 	}
 
@@ -674,25 +1265,77 @@ func (ex *Executor) ListMachines(ctx context.Context) (map[string]string, error)
 	return result, nil
 }
 
+// serverTagMigration records the ownership markers for which all servers marked only by their metadata have been tagged
+// by this process. Until then, listServers cannot rely on the server-side filtering by tags.
+type serverTagMigration struct {
+	mutex sync.Mutex
+	done  sets.Set[string]
+}
+
+var migratedServerTags = &serverTagMigration{done: sets.New[string]()}
+
+func (m *serverTagMigration) isDone(key string) bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.done.Has(key)
+}
+
+func (m *serverTagMigration) markDone(key string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.done.Insert(key)
+}
+
 // ListServers lists all servers with the appropriate tags.
-func (ex *Executor) listServers(_ context.Context) ([]servers.Server, error) {
+func (ex *Executor) listServers(ctx context.Context) ([]servers.Server, error) {
 	searchClusterName, searchNodeRole, ok := findMandatoryTags(ex.Config.Spec.Tags)
 	if !ok {
 		klog.Warningf("list operation can not proceed: cluster/role tags are missing")
 		return nil, fmt.Errorf("list operation can not proceed: cluster/role tags are missing")
 	}
 
-	allServers, err := ex.Compute.ListServers(&servers.ListOpts{})
+	ownershipTags, ok := ex.ownershipTags()
+	if !ok {
+		return ex.listServersByMetadata(ctx, searchClusterName, searchNodeRole)
+	}
+
+	// servers created before server tags were supported are only marked by their metadata, all servers are listed once to
+	// tag them, so that they are found by the server-side filtering afterwards
+	migrationKey := ex.Config.Spec.Region + "/" + strings.Join(ownershipTags, ",")
+	if !migratedServerTags.isDone(migrationKey) {
+		result, err := ex.listServersByMetadata(ctx, searchClusterName, searchNodeRole)
+		if err != nil {
+			return nil, err
+		}
+		migrated := true
+		for i := range result {
+			if err := ex.reconcileServerTags(ctx, &result[i]); err != nil {
+				klog.Warningf("failed to reconcile tags of server [ID=%q]: %v", result[i].ID, err)
+				migrated = false
+			}
+		}
+		if migrated {
+			migratedServerTags.markDone(migrationKey)
+		}
+		return result, nil
+	}
+
+	return ex.Compute.ListServers(ctx, &servers.ListOpts{
+		Tags: strings.Join(ownershipTags, ","),
+	})
+}
+
+// listServersByMetadata lists all servers of the project and filters them client-side by their ownership markers.
+func (ex *Executor) listServersByMetadata(ctx context.Context, searchClusterName, searchNodeRole string) ([]servers.Server, error) {
+	allServers, err := ex.Compute.ListServers(ctx, &servers.ListOpts{})
 	if err != nil {
 		return nil, err
 	}
 
 	var result []servers.Server
 	for _, server := range allServers {
-		if _, nameOk := server.Metadata[searchClusterName]; nameOk {
-			if _, roleOk := server.Metadata[searchNodeRole]; roleOk {
-				result = append(result, server)
-			}
+		if hasOwnershipMarkers(&server, searchClusterName, searchNodeRole) {
+			result = append(result, server)
 		}
 	}
 
@@ -8,16 +8,25 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/gophercloud/gophercloud"
 	"github.com/gophercloud/gophercloud/openstack/blockstorage/v3/volumes"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/bootfromvolume"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/servergroups"
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+	"github.com/gophercloud/gophercloud/openstack/imageservice/v2/images"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/floatingips"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/ports"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/subnets"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"go.uber.org/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/utils/pointer"
+	"k8s.io/utils/ptr"
 
 	"github.com/gardener/machine-controller-manager-provider-openstack/pkg/apis/cloudprovider"
 	"github.com/gardener/machine-controller-manager-provider-openstack/pkg/apis/openstack"
@@ -31,13 +40,16 @@ var _ = Describe("Executor", func() {
 		networkID = "networkID"
 	)
 	var (
-		ctrl    *gomock.Controller
-		compute *mocks.MockCompute
-		network *mocks.MockNetwork
-		storage *mocks.MockStorage
-		tags    map[string]string
-		cfg     *openstack.MachineProviderConfig
-		ctx     context.Context
+		ctrl          *gomock.Controller
+		compute       *mocks.MockCompute
+		network       *mocks.MockNetwork
+		storage       *mocks.MockStorage
+		image         *mocks.MockImage
+		tags          map[string]string
+		cfg           *openstack.MachineProviderConfig
+		ctx           context.Context
+		serverTags    bool
+		serverDetails bool
 	)
 
 	BeforeEach(func() {
@@ -46,6 +58,13 @@ var _ = Describe("Executor", func() {
 		compute = mocks.NewMockCompute(ctrl)
 		network = mocks.NewMockNetwork(ctrl)
 		storage = mocks.NewMockStorage(ctrl)
+		image = mocks.NewMockImage(ctrl)
+		serverTags = false
+		serverDetails = false
+		compute.EXPECT().Supports(client.FeatureServerTags).DoAndReturn(func(client.Feature) bool { return serverTags }).AnyTimes()
+		compute.EXPECT().Supports(client.FeatureServerCreateTags).DoAndReturn(func(client.Feature) bool { return serverTags }).AnyTimes()
+		compute.EXPECT().Supports(client.FeatureServerDescription).DoAndReturn(func(client.Feature) bool { return serverDetails }).AnyTimes()
+		compute.EXPECT().Supports(client.FeatureServerHostname).DoAndReturn(func(client.Feature) bool { return serverDetails }).AnyTimes()
 
 		tags = map[string]string{
 			fmt.Sprintf("%sfoo", cloudprovider.ServerTagClusterPrefix): "1",
@@ -97,33 +116,78 @@ var _ = Describe("Executor", func() {
 				Config:  cfg,
 			}
 
-			compute.EXPECT().ListServers(&servers.ListOpts{Name: machineName}).Return([]servers.Server{}, nil)
-			compute.EXPECT().ImageIDFromName(imageName).Return("imageID", nil)
-			compute.EXPECT().FlavorIDFromName(flavorName).Return("flavorID", nil)
-			compute.EXPECT().CreateServer(gomock.Any()).Return(&servers.Server{
+			compute.EXPECT().ListServers(gomock.Any(), &servers.ListOpts{Name: machineName}).Return([]servers.Server{}, nil)
+			compute.EXPECT().ImageIDFromName(gomock.Any(), imageName).Return("imageID", nil)
+			compute.EXPECT().FlavorIDFromName(gomock.Any(), flavorName).Return("flavorID", nil)
+			compute.EXPECT().CreateServer(gomock.Any(), gomock.Any()).Return(&servers.Server{
 				ID: serverID,
 			}, nil)
 			gomock.InOrder(
-				compute.EXPECT().GetServer(serverID).Return(&servers.Server{
+				compute.EXPECT().GetServer(gomock.Any(), serverID).Return(&servers.Server{
 					ID:     serverID,
 					Status: client.ServerStatusBuild,
 				}, nil),
-				compute.EXPECT().GetServer(serverID).Return(&servers.Server{
+				compute.EXPECT().GetServer(gomock.Any(), serverID).Return(&servers.Server{
 					ID:     serverID,
 					Status: client.ServerStatusActive,
 				}, nil))
-			network.EXPECT().ListPorts(&ports.ListOpts{
-				DeviceID: serverID,
-			}).Return([]ports.Port{{NetworkID: networkID, ID: portID}}, nil)
-			network.EXPECT().UpdatePort(portID, ports.UpdateOpts{
-				AllowedAddressPairs: &[]ports.AddressPair{{IPAddress: podCidr}},
-			}).Return(nil)
 
 			providerId, err := ex.CreateMachine(ctx, machineName, nil)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(providerId).To(Equal(encodeProviderID(region, serverID)))
 		})
 
+		It("should tag the server on creation if server tags are supported", func() {
+			serverTags = true
+			ex := &Executor{
+				Compute: compute,
+				Network: network,
+				Config:  cfg,
+			}
+
+			compute.EXPECT().ListServers(gomock.Any(), &servers.ListOpts{Name: machineName}).Return([]servers.Server{}, nil)
+			compute.EXPECT().ImageIDFromName(gomock.Any(), imageName).Return("imageID", nil)
+			compute.EXPECT().FlavorIDFromName(gomock.Any(), flavorName).Return("flavorID", nil)
+			compute.EXPECT().CreateServer(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, opts servers.CreateOptsBuilder) (*servers.Server, error) {
+				m, err := opts.ToServerCreateMap()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(m["server"]).To(HaveKeyWithValue("tags", ConsistOf(
+					fmt.Sprintf("%sfoo", cloudprovider.ServerTagClusterPrefix),
+					fmt.Sprintf("%sfoo", cloudprovider.ServerTagRolePrefix),
+				)))
+				return &servers.Server{ID: serverID}, nil
+			})
+			compute.EXPECT().GetServer(gomock.Any(), serverID).Return(&servers.Server{ID: serverID, Status: client.ServerStatusActive}, nil)
+
+			providerId, err := ex.CreateMachine(ctx, machineName, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(providerId).To(Equal(encodeProviderID(region, serverID)))
+		})
+
+		It("should set the description and hostname of the server if supported", func() {
+			serverDetails = true
+			ex := &Executor{
+				Compute: compute,
+				Network: network,
+				Config:  cfg,
+			}
+
+			compute.EXPECT().ListServers(gomock.Any(), &servers.ListOpts{Name: machineName}).Return([]servers.Server{}, nil)
+			compute.EXPECT().ImageIDFromName(gomock.Any(), imageName).Return("imageID", nil)
+			compute.EXPECT().FlavorIDFromName(gomock.Any(), flavorName).Return("flavorID", nil)
+			compute.EXPECT().CreateServer(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, opts servers.CreateOptsBuilder) (*servers.Server, error) {
+				m, err := opts.ToServerCreateMap()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(m["server"]).To(HaveKeyWithValue("description", ContainSubstring(machineName)))
+				Expect(m["server"]).To(HaveKeyWithValue("hostname", machineName))
+				return &servers.Server{ID: serverID}, nil
+			})
+			compute.EXPECT().GetServer(gomock.Any(), serverID).Return(&servers.Server{ID: serverID, Status: client.ServerStatusActive}, nil)
+
+			_, err := ex.CreateMachine(ctx, machineName, nil)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
 		It("should succeed when spec contains subnet", func() {
 			subnetID := "subnetID"
 
@@ -134,22 +198,18 @@ var _ = Describe("Executor", func() {
 				Config:  cfg,
 			}
 
-			compute.EXPECT().ListServers(&servers.ListOpts{Name: machineName}).Return([]servers.Server{}, nil)
-			network.EXPECT().GetSubnet(subnetID).Return(&subnets.Subnet{}, nil)
-			network.EXPECT().PortIDFromName(machineName).Return("", gophercloud.ErrResourceNotFound{})
-			network.EXPECT().CreatePort(gomock.Any()).Return(&ports.Port{ID: portID, Name: machineName}, nil)
-			network.EXPECT().TagPort(gomock.Any(), gomock.Any()).Return(nil)
-			compute.EXPECT().ImageIDFromName(imageName).Return("imageID", nil)
-			compute.EXPECT().FlavorIDFromName(flavorName).Return("flavorID", nil)
-			compute.EXPECT().CreateServer(gomock.Any()).Return(&servers.Server{ID: serverID}, nil)
+			compute.EXPECT().ListServers(gomock.Any(), &servers.ListOpts{Name: machineName}).Return([]servers.Server{}, nil)
+			network.EXPECT().GetSubnet(gomock.Any(), subnetID).Return(&subnets.Subnet{}, nil)
+			network.EXPECT().PortIDFromName(gomock.Any(), machineName).Return("", gophercloud.ErrResourceNotFound{})
+			network.EXPECT().CreatePort(gomock.Any(), gomock.Any()).Return(&ports.Port{ID: portID, Name: machineName}, nil)
+			network.EXPECT().TagPort(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+			compute.EXPECT().ImageIDFromName(gomock.Any(), imageName).Return("imageID", nil)
+			compute.EXPECT().FlavorIDFromName(gomock.Any(), flavorName).Return("flavorID", nil)
+			compute.EXPECT().CreateServer(gomock.Any(), gomock.Any()).Return(&servers.Server{ID: serverID}, nil)
 			gomock.InOrder(
-				compute.EXPECT().GetServer(serverID).Return(&servers.Server{ID: serverID, Status: client.ServerStatusBuild}, nil),
-				compute.EXPECT().GetServer(serverID).Return(&servers.Server{ID: serverID, Status: client.ServerStatusActive}, nil),
+				compute.EXPECT().GetServer(gomock.Any(), serverID).Return(&servers.Server{ID: serverID, Status: client.ServerStatusBuild}, nil),
+				compute.EXPECT().GetServer(gomock.Any(), serverID).Return(&servers.Server{ID: serverID, Status: client.ServerStatusActive}, nil),
 			)
-			network.EXPECT().ListPorts(&ports.ListOpts{DeviceID: serverID}).Return([]ports.Port{{NetworkID: networkID, ID: portID}}, nil)
-			network.EXPECT().UpdatePort(portID, ports.UpdateOpts{
-				AllowedAddressPairs: &[]ports.AddressPair{{IPAddress: podCidr}},
-			}).Return(nil)
 
 			providerId, err := ex.CreateMachine(ctx, machineName, nil)
 			Expect(err).ToNot(HaveOccurred())
@@ -171,30 +231,495 @@ var _ = Describe("Executor", func() {
 				Config:  cfg,
 			}
 
-			compute.EXPECT().ListServers(&servers.ListOpts{Name: machineName}).Return([]servers.Server{}, nil)
-			compute.EXPECT().ImageIDFromName(imageName).Return("imageID", nil)
-			compute.EXPECT().FlavorIDFromName(flavorName).Return("flavorID", nil)
-			storage.EXPECT().VolumeIDFromName(machineName).Return("", gophercloud.ErrResourceNotFound{})
+			compute.EXPECT().ListServers(gomock.Any(), &servers.ListOpts{Name: machineName}).Return([]servers.Server{}, nil)
+			compute.EXPECT().ImageIDFromName(gomock.Any(), imageName).Return("imageID", nil)
+			compute.EXPECT().FlavorIDFromName(gomock.Any(), flavorName).Return("flavorID", nil)
+			storage.EXPECT().VolumeIDFromName(gomock.Any(), machineName).Return("", gophercloud.ErrResourceNotFound{})
 			gomock.InOrder(
-				storage.EXPECT().GetVolume(volumeID).Return(&volumes.Volume{ID: volumeID, Status: client.VolumeStatusCreating}, nil),
-				storage.EXPECT().GetVolume(volumeID).Return(&volumes.Volume{ID: volumeID, Status: client.VolumeStatusAvailable}, nil),
+				storage.EXPECT().GetVolume(gomock.Any(), volumeID).Return(&volumes.Volume{ID: volumeID, Status: client.VolumeStatusCreating}, nil),
+				storage.EXPECT().GetVolume(gomock.Any(), volumeID).Return(&volumes.Volume{ID: volumeID, Status: client.VolumeStatusAvailable}, nil),
 			)
-			storage.EXPECT().CreateVolume(gomock.Any()).Return(&volumes.Volume{ID: volumeID}, nil)
-			compute.EXPECT().BootFromVolume(gomock.Any()).Return(&servers.Server{ID: serverID}, nil)
+			storage.EXPECT().CreateVolume(gomock.Any(), gomock.Any()).Return(&volumes.Volume{ID: volumeID}, nil)
+			compute.EXPECT().BootFromVolume(gomock.Any(), gomock.Any()).Return(&servers.Server{ID: serverID}, nil)
 			gomock.InOrder(
-				compute.EXPECT().GetServer(serverID).Return(&servers.Server{ID: serverID, Status: client.ServerStatusBuild}, nil),
-				compute.EXPECT().GetServer(serverID).Return(&servers.Server{ID: serverID, Status: client.ServerStatusActive}, nil),
+				compute.EXPECT().GetServer(gomock.Any(), serverID).Return(&servers.Server{ID: serverID, Status: client.ServerStatusBuild}, nil),
+				compute.EXPECT().GetServer(gomock.Any(), serverID).Return(&servers.Server{ID: serverID, Status: client.ServerStatusActive}, nil),
 			)
-			network.EXPECT().ListPorts(&ports.ListOpts{DeviceID: serverID}).Return([]ports.Port{{NetworkID: networkID, ID: portID}}, nil)
-			network.EXPECT().UpdatePort(portID, ports.UpdateOpts{
-				AllowedAddressPairs: &[]ports.AddressPair{{IPAddress: podCidr}},
-			}).Return(nil)
 
 			providerId, err := ex.CreateMachine(ctx, machineName, nil)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(providerId).To(Equal(encodeProviderID(region, serverID)))
 		})
 
+		It("should create and attach data volumes", func() {
+			var (
+				volumeType = "ssd"
+				volumeID   = "volumeID"
+			)
+			cfg.Spec.DataVolumes = []openstack.DataVolume{{Name: "etcd", Size: 10, Type: &volumeType}}
+			ex := &Executor{
+				Compute: compute,
+				Network: network,
+				Storage: storage,
+				Config:  cfg,
+			}
+
+			compute.EXPECT().ListServers(gomock.Any(), &servers.ListOpts{Name: machineName}).Return([]servers.Server{}, nil)
+			compute.EXPECT().ImageIDFromName(gomock.Any(), imageName).Return("imageID", nil)
+			compute.EXPECT().FlavorIDFromName(gomock.Any(), flavorName).Return("flavorID", nil)
+			storage.EXPECT().VolumeIDFromName(gomock.Any(), machineName+"-etcd").Return("", gophercloud.ErrResourceNotFound{})
+			storage.EXPECT().CreateVolume(gomock.Any(), volumes.CreateOpts{
+				Name:       machineName + "-etcd",
+				Size:       10,
+				VolumeType: volumeType,
+				Metadata:   tags,
+			}).Return(&volumes.Volume{ID: volumeID}, nil)
+			storage.EXPECT().GetVolume(gomock.Any(), volumeID).Return(&volumes.Volume{ID: volumeID, Status: client.VolumeStatusAvailable}, nil)
+			compute.EXPECT().BootFromVolume(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, opts servers.CreateOptsBuilder) (*servers.Server, error) {
+				ext, ok := opts.(*bootfromvolume.CreateOptsExt)
+				Expect(ok).To(BeTrue())
+				Expect(ext.BlockDevice).To(HaveLen(2))
+				Expect(ext.BlockDevice[0].DestinationType).To(BeEquivalentTo("local"))
+				Expect(ext.BlockDevice[1].UUID).To(Equal(volumeID))
+				Expect(ext.BlockDevice[1].BootIndex).To(Equal(-1))
+				return &servers.Server{ID: serverID}, nil
+			})
+			gomock.InOrder(
+				compute.EXPECT().GetServer(gomock.Any(), serverID).Return(&servers.Server{ID: serverID, Status: client.ServerStatusBuild}, nil),
+				compute.EXPECT().GetServer(gomock.Any(), serverID).Return(&servers.Server{ID: serverID, Status: client.ServerStatusActive}, nil),
+			)
+
+			providerId, err := ex.CreateMachine(ctx, machineName, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(providerId).To(Equal(encodeProviderID(region, serverID)))
+		})
+
+		It("should mark the block device mapping of multiattach data volumes", func() {
+			volumeID := "volumeID"
+			cfg.Spec.DataVolumes = []openstack.DataVolume{{Name: "shared", Size: 10, Type: ptr.To("multiattach")}}
+			ex := &Executor{
+				Compute: compute,
+				Network: network,
+				Storage: storage,
+				Config:  cfg,
+			}
+
+			compute.EXPECT().ListServers(gomock.Any(), &servers.ListOpts{Name: machineName}).Return([]servers.Server{}, nil)
+			compute.EXPECT().ImageIDFromName(gomock.Any(), imageName).Return("imageID", nil)
+			compute.EXPECT().FlavorIDFromName(gomock.Any(), flavorName).Return("flavorID", nil)
+			storage.EXPECT().VolumeIDFromName(gomock.Any(), machineName+"-shared").Return("", gophercloud.ErrResourceNotFound{})
+			storage.EXPECT().CreateVolume(gomock.Any(), gomock.Any()).Return(&volumes.Volume{ID: volumeID}, nil)
+			storage.EXPECT().GetVolume(gomock.Any(), volumeID).Return(&volumes.Volume{ID: volumeID, Status: client.VolumeStatusAvailable, Multiattach: true}, nil)
+			compute.EXPECT().BootFromVolume(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, opts servers.CreateOptsBuilder) (*servers.Server, error) {
+				ext, ok := opts.(client.MultiattachCreateOptsExt)
+				Expect(ok).To(BeTrue())
+				Expect(ext.CreateOptsBuilder.(*bootfromvolume.CreateOptsExt).BlockDevice[1].UUID).To(Equal(volumeID))
+				return &servers.Server{ID: serverID}, nil
+			})
+			compute.EXPECT().GetServer(gomock.Any(), serverID).Return(&servers.Server{ID: serverID, Status: client.ServerStatusActive}, nil)
+
+			providerId, err := ex.CreateMachine(ctx, machineName, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(providerId).To(Equal(encodeProviderID(region, serverID)))
+		})
+
+		It("should delete the created data volumes which are kept on termination if the creation fails", func() {
+			cfg.Spec.DataVolumes = []openstack.DataVolume{
+				{Name: "created", Size: 10, DeleteOnTermination: ptr.To(false)},
+				{Name: "existing", Size: 10, DeleteOnTermination: ptr.To(false)},
+			}
+			ex := &Executor{
+				Compute: compute,
+				Network: network,
+				Storage: storage,
+				Config:  cfg,
+			}
+
+			compute.EXPECT().ListServers(gomock.Any(), &servers.ListOpts{Name: machineName}).Return([]servers.Server{}, nil).Times(2)
+			compute.EXPECT().ImageIDFromName(gomock.Any(), imageName).Return("imageID", nil)
+			compute.EXPECT().FlavorIDFromName(gomock.Any(), flavorName).Return("flavorID", nil)
+			gomock.InOrder(
+				storage.EXPECT().VolumeIDFromName(gomock.Any(), machineName+"-created").Return("", gophercloud.ErrResourceNotFound{}),
+				storage.EXPECT().CreateVolume(gomock.Any(), gomock.Any()).Return(&volumes.Volume{ID: "created"}, nil),
+				storage.EXPECT().GetVolume(gomock.Any(), "created").Return(&volumes.Volume{ID: "created", Status: client.VolumeStatusAvailable}, nil),
+				storage.EXPECT().VolumeIDFromName(gomock.Any(), machineName+"-existing").Return("existing", nil),
+				storage.EXPECT().GetVolume(gomock.Any(), "existing").Return(&volumes.Volume{ID: "existing", Status: client.VolumeStatusAvailable}, nil),
+				compute.EXPECT().BootFromVolume(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("conflict")),
+				// only the volume created by this attempt is deleted
+				storage.EXPECT().VolumeIDFromName(gomock.Any(), machineName+"-created").Return("created", nil),
+				storage.EXPECT().GetVolume(gomock.Any(), "created").Return(&volumes.Volume{ID: "created", Status: client.VolumeStatusAvailable}, nil),
+				storage.EXPECT().DeleteVolume(gomock.Any(), "created").Return(nil),
+				storage.EXPECT().GetVolume(gomock.Any(), "created").Return(nil, gophercloud.ErrResourceNotFound{}),
+			)
+
+			_, err := ex.CreateMachine(ctx, machineName, nil)
+			Expect(err).To(MatchError(ContainSubstring("conflict")))
+		})
+
+		It("should retry in the fallback availability zone on insufficient capacity", func() {
+			cfg.Spec.AvailabilityZone = "zone-a"
+			cfg.Spec.FallbackAvailabilityZones = []string{"zone-b"}
+			ex := &Executor{
+				Compute: compute,
+				Network: network,
+				Config:  cfg,
+			}
+
+			zoneOf := func(opts servers.CreateOptsBuilder) (string, map[string]interface{}) {
+				m, err := opts.ToServerCreateMap()
+				Expect(err).ToNot(HaveOccurred())
+				server := m["server"].(map[string]interface{})
+				return server["availability_zone"].(string), server["metadata"].(map[string]interface{})
+			}
+
+			compute.EXPECT().ListServers(gomock.Any(), &servers.ListOpts{Name: machineName}).Return([]servers.Server{}, nil).Times(2)
+			compute.EXPECT().ImageIDFromName(gomock.Any(), imageName).Return("imageID", nil).Times(2)
+			compute.EXPECT().FlavorIDFromName(gomock.Any(), flavorName).Return("flavorID", nil).Times(2)
+			gomock.InOrder(
+				compute.EXPECT().CreateServer(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, opts servers.CreateOptsBuilder) (*servers.Server, error) {
+					zone, _ := zoneOf(opts)
+					Expect(zone).To(Equal("zone-a"))
+					return nil, fmt.Errorf("%s. There are not enough hosts available", NoValidHost)
+				}),
+				compute.EXPECT().CreateServer(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, opts servers.CreateOptsBuilder) (*servers.Server, error) {
+					zone, metadata := zoneOf(opts)
+					Expect(zone).To(Equal("zone-b"))
+					Expect(metadata).To(HaveKeyWithValue(cloudprovider.ServerMetadataAvailabilityZone, "zone-b"))
+					return &servers.Server{ID: serverID}, nil
+				}),
+				compute.EXPECT().GetServer(gomock.Any(), serverID).Return(&servers.Server{ID: serverID, Status: client.ServerStatusActive}, nil),
+			)
+
+			providerId, err := ex.CreateMachine(ctx, machineName, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(providerId).To(Equal(encodeProviderID(region, serverID)))
+		})
+
+		It("should recreate the volumes in the fallback availability zone", func() {
+			var (
+				diskType = "standard_hdd"
+				zoneA    = "zone-a"
+				zoneB    = "zone-b"
+			)
+			cfg.Spec.AvailabilityZone = zoneA
+			cfg.Spec.FallbackAvailabilityZones = []string{zoneB}
+			cfg.Spec.RootDiskType = &diskType
+			cfg.Spec.RootDiskSize = 50
+			cfg.Spec.DataVolumes = []openstack.DataVolume{{Name: "etcd", Size: 10, DeleteOnTermination: ptr.To(false)}}
+			ex := &Executor{
+				Compute: compute,
+				Network: network,
+				Storage: storage,
+				Config:  cfg,
+			}
+
+			compute.EXPECT().ListServers(gomock.Any(), &servers.ListOpts{Name: machineName}).Return([]servers.Server{}, nil).Times(2)
+			compute.EXPECT().ImageIDFromName(gomock.Any(), imageName).Return("imageID", nil).Times(2)
+			compute.EXPECT().FlavorIDFromName(gomock.Any(), flavorName).Return("flavorID", nil).Times(2)
+			gomock.InOrder(
+				// the first attempt in zone-a
+				storage.EXPECT().VolumeIDFromName(gomock.Any(), machineName).Return("", gophercloud.ErrResourceNotFound{}),
+				storage.EXPECT().CreateVolume(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, opts volumes.CreateOptsBuilder) (*volumes.Volume, error) {
+					Expect(opts.(volumes.CreateOpts).AvailabilityZone).To(Equal(zoneA))
+					return &volumes.Volume{ID: "root-a"}, nil
+				}),
+				storage.EXPECT().GetVolume(gomock.Any(), "root-a").Return(&volumes.Volume{ID: "root-a", Status: client.VolumeStatusAvailable}, nil),
+				storage.EXPECT().VolumeIDFromName(gomock.Any(), machineName+"-etcd").Return("", gophercloud.ErrResourceNotFound{}),
+				storage.EXPECT().CreateVolume(gomock.Any(), gomock.Any()).Return(&volumes.Volume{ID: "etcd-a"}, nil),
+				storage.EXPECT().GetVolume(gomock.Any(), "etcd-a").Return(&volumes.Volume{ID: "etcd-a", Status: client.VolumeStatusAvailable}, nil),
+				compute.EXPECT().BootFromVolume(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("%s. There are not enough hosts available", NoValidHost)),
+				// the root volume is deleted before the next attempt, the deletion is awaited
+				storage.EXPECT().VolumeIDFromName(gomock.Any(), machineName).Return("root-a", nil),
+				storage.EXPECT().GetVolume(gomock.Any(), "root-a").Return(&volumes.Volume{ID: "root-a", Status: client.VolumeStatusAvailable}, nil),
+				storage.EXPECT().DeleteVolume(gomock.Any(), "root-a").Return(nil),
+				storage.EXPECT().GetVolume(gomock.Any(), "root-a").Return(nil, gophercloud.ErrResourceNotFound{}),
+				// the second attempt in zone-b recreates the kept data volume, since it is in zone-a
+				storage.EXPECT().VolumeIDFromName(gomock.Any(), machineName).Return("", gophercloud.ErrResourceNotFound{}),
+				storage.EXPECT().CreateVolume(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, opts volumes.CreateOptsBuilder) (*volumes.Volume, error) {
+					Expect(opts.(volumes.CreateOpts).AvailabilityZone).To(Equal(zoneB))
+					return &volumes.Volume{ID: "root-b"}, nil
+				}),
+				storage.EXPECT().GetVolume(gomock.Any(), "root-b").Return(&volumes.Volume{ID: "root-b", Status: client.VolumeStatusAvailable}, nil),
+				storage.EXPECT().VolumeIDFromName(gomock.Any(), machineName+"-etcd").Return("etcd-a", nil),
+				storage.EXPECT().GetVolume(gomock.Any(), "etcd-a").Return(&volumes.Volume{ID: "etcd-a", AvailabilityZone: zoneA, Status: client.VolumeStatusAvailable}, nil),
+				storage.EXPECT().VolumeIDFromName(gomock.Any(), machineName+"-etcd").Return("etcd-a", nil),
+				storage.EXPECT().GetVolume(gomock.Any(), "etcd-a").Return(&volumes.Volume{ID: "etcd-a", AvailabilityZone: zoneA, Status: client.VolumeStatusAvailable}, nil),
+				storage.EXPECT().DeleteVolume(gomock.Any(), "etcd-a").Return(nil),
+				storage.EXPECT().GetVolume(gomock.Any(), "etcd-a").Return(nil, gophercloud.ErrResourceNotFound{}),
+				storage.EXPECT().CreateVolume(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, opts volumes.CreateOptsBuilder) (*volumes.Volume, error) {
+					Expect(opts.(volumes.CreateOpts).AvailabilityZone).To(Equal(zoneB))
+					return &volumes.Volume{ID: "etcd-b"}, nil
+				}),
+				storage.EXPECT().GetVolume(gomock.Any(), "etcd-b").Return(&volumes.Volume{ID: "etcd-b", AvailabilityZone: zoneB, Status: client.VolumeStatusAvailable}, nil),
+				compute.EXPECT().BootFromVolume(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, opts servers.CreateOptsBuilder) (*servers.Server, error) {
+					ext := opts.(*bootfromvolume.CreateOptsExt)
+					Expect(ext.BlockDevice[0].UUID).To(Equal("root-b"))
+					Expect(ext.BlockDevice[1].UUID).To(Equal("etcd-b"))
+					return &servers.Server{ID: serverID}, nil
+				}),
+				compute.EXPECT().GetServer(gomock.Any(), serverID).Return(&servers.Server{ID: serverID, Status: client.ServerStatusActive}, nil),
+			)
+
+			providerId, err := ex.CreateMachine(ctx, machineName, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(providerId).To(Equal(encodeProviderID(region, serverID)))
+		})
+
+		It("should retry with the fallback flavor on exhausted quota", func() {
+			cfg.Spec.FallbackFlavorNames = []string{"small"}
+			ex := &Executor{
+				Compute: compute,
+				Network: network,
+				Config:  cfg,
+			}
+
+			compute.EXPECT().ListServers(gomock.Any(), &servers.ListOpts{Name: machineName}).Return([]servers.Server{}, nil).Times(2)
+			compute.EXPECT().ImageIDFromName(gomock.Any(), imageName).Return("imageID", nil).Times(2)
+			gomock.InOrder(
+				compute.EXPECT().FlavorIDFromName(gomock.Any(), flavorName).Return("flavorID", nil),
+				compute.EXPECT().CreateServer(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("%s for cores: Requested 8, but already used 96 of 100 cores", QuotaExceeded)),
+				compute.EXPECT().FlavorIDFromName(gomock.Any(), "small").Return("smallFlavorID", nil),
+				compute.EXPECT().CreateServer(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, opts servers.CreateOptsBuilder) (*servers.Server, error) {
+					m, err := opts.ToServerCreateMap()
+					Expect(err).ToNot(HaveOccurred())
+					server := m["server"].(map[string]interface{})
+					Expect(server["flavorRef"]).To(Equal("smallFlavorID"))
+					Expect(server["metadata"]).To(HaveKeyWithValue(cloudprovider.ServerMetadataFlavor, "small"))
+					return &servers.Server{ID: serverID}, nil
+				}),
+				compute.EXPECT().GetServer(gomock.Any(), serverID).Return(&servers.Server{ID: serverID, Status: client.ServerStatusActive}, nil),
+			)
+
+			providerId, err := ex.CreateMachine(ctx, machineName, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(providerId).To(Equal(encodeProviderID(region, serverID)))
+		})
+
+		It("should not retry in the fallback availability zone on exhausted quota", func() {
+			cfg.Spec.AvailabilityZone = "zone-a"
+			cfg.Spec.FallbackAvailabilityZones = []string{"zone-b"}
+			ex := &Executor{
+				Compute: compute,
+				Network: network,
+				Config:  cfg,
+			}
+
+			compute.EXPECT().ListServers(gomock.Any(), &servers.ListOpts{Name: machineName}).Return([]servers.Server{}, nil).Times(2)
+			compute.EXPECT().ImageIDFromName(gomock.Any(), imageName).Return("imageID", nil)
+			compute.EXPECT().FlavorIDFromName(gomock.Any(), flavorName).Return("flavorID", nil)
+			compute.EXPECT().CreateServer(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("%s for instances: Requested 1, but already used 100 of 100 instances", QuotaExceeded))
+
+			_, err := ex.CreateMachine(ctx, machineName, nil)
+			Expect(err).To(MatchError(ContainSubstring(QuotaExceeded)))
+		})
+
+		It("should create the managed server group and schedule the server into it", func() {
+			cfg.Spec.ManagedServerGroup = &openstack.ManagedServerGroup{Name: "workers", Policy: "soft-anti-affinity"}
+			groupID := "3d5d4ec5-6f0c-4b4c-9e58-2f6bd8c1f0a7"
+			ex := &Executor{
+				Compute: compute,
+				Network: network,
+				Config:  cfg,
+			}
+
+			compute.EXPECT().ListServers(gomock.Any(), &servers.ListOpts{Name: machineName}).Return([]servers.Server{}, nil)
+			compute.EXPECT().ImageIDFromName(gomock.Any(), imageName).Return("imageID", nil)
+			compute.EXPECT().FlavorIDFromName(gomock.Any(), flavorName).Return("flavorID", nil)
+			gomock.InOrder(
+				compute.EXPECT().ListServerGroups(gomock.Any(), gomock.Any()).Return([]servergroups.ServerGroup{{ID: "other", Name: "other"}}, nil),
+				compute.EXPECT().CreateServerGroup(gomock.Any(), servergroups.CreateOpts{
+					Name:     "foo-foo-workers",
+					Policies: []string{"soft-anti-affinity"},
+				}).Return(&servergroups.ServerGroup{ID: groupID, Name: "foo-foo-workers", Policies: []string{"soft-anti-affinity"}}, nil),
+				compute.EXPECT().ListServerGroups(gomock.Any(), gomock.Any()).Return([]servergroups.ServerGroup{
+					{ID: groupID, Name: "foo-foo-workers", Policies: []string{"soft-anti-affinity"}},
+				}, nil),
+				compute.EXPECT().CreateServer(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, opts servers.CreateOptsBuilder) (*servers.Server, error) {
+					m, err := opts.ToServerCreateMap()
+					Expect(err).ToNot(HaveOccurred())
+					Expect(m["os:scheduler_hints"]).To(HaveKeyWithValue("group", groupID))
+					return &servers.Server{ID: serverID}, nil
+				}),
+				compute.EXPECT().GetServer(gomock.Any(), serverID).Return(&servers.Server{ID: serverID, Status: client.ServerStatusActive}, nil),
+				compute.EXPECT().ListServerGroups(gomock.Any(), gomock.Any()).Return([]servergroups.ServerGroup{
+					{ID: groupID, Name: "foo-foo-workers", Policies: []string{"soft-anti-affinity"}, Members: []string{serverID}},
+				}, nil),
+			)
+
+			providerId, err := ex.CreateMachine(ctx, machineName, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(providerId).To(Equal(encodeProviderID(region, serverID)))
+		})
+
+		It("should fail the creation if the managed server group was deleted concurrently", func() {
+			cfg.Spec.ManagedServerGroup = &openstack.ManagedServerGroup{Name: "workers", Policy: "anti-affinity"}
+			groupID := "3d5d4ec5-6f0c-4b4c-9e58-2f6bd8c1f0a7"
+			ex := &Executor{
+				Compute: compute,
+				Network: network,
+				Config:  cfg,
+			}
+
+			compute.EXPECT().ImageIDFromName(gomock.Any(), imageName).Return("imageID", nil)
+			compute.EXPECT().FlavorIDFromName(gomock.Any(), flavorName).Return("flavorID", nil)
+			gomock.InOrder(
+				compute.EXPECT().ListServers(gomock.Any(), &servers.ListOpts{Name: machineName}).Return([]servers.Server{}, nil),
+				compute.EXPECT().ListServerGroups(gomock.Any(), gomock.Any()).Return([]servergroups.ServerGroup{
+					{ID: groupID, Name: "foo-foo-workers", Policies: []string{"anti-affinity"}},
+				}, nil),
+				compute.EXPECT().CreateServer(gomock.Any(), gomock.Any()).Return(&servers.Server{ID: serverID}, nil),
+				compute.EXPECT().GetServer(gomock.Any(), serverID).Return(&servers.Server{ID: serverID, Status: client.ServerStatusActive}, nil),
+				// the empty server group was deleted by a concurrent DeleteMachine before the server was scheduled
+				compute.EXPECT().ListServerGroups(gomock.Any(), gomock.Any()).Return(nil, nil),
+				// the server is deleted again, so that its creation is retried with a recreated server group
+				compute.EXPECT().ListServers(gomock.Any(), &servers.ListOpts{Name: machineName}).Return([]servers.Server{{ID: serverID, Name: machineName, Metadata: cfg.Spec.Tags}}, nil),
+				compute.EXPECT().DeleteServer(gomock.Any(), serverID).Return(nil),
+				compute.EXPECT().GetServer(gomock.Any(), serverID).Return(nil, gophercloud.ErrResourceNotFound{}),
+				compute.EXPECT().ListServerGroups(gomock.Any(), gomock.Any()).Return(nil, nil),
+			)
+
+			_, err := ex.CreateMachine(ctx, machineName, nil)
+			Expect(err).To(MatchError(ContainSubstring("is not a member of server group")))
+		})
+
+		It("should pass the scheduler hints", func() {
+			var (
+				serverGroupID = "3d5d4ec5-6f0c-4b4c-9e58-2f6bd8c1f0a7"
+				otherID       = "8b0e7d4e-2b7a-4a43-9d3c-5f1e2f2d5c11"
+				sameID        = "c6a7b1a2-3d4e-4f50-8a6b-7c8d9e0f1a2b"
+			)
+			cfg.Spec.ServerGroupID = ptr.To(serverGroupID)
+			cfg.Spec.SchedulerHints = &openstack.SchedulerHints{
+				DifferentHost:        []openstack.ServerReference{{Name: "etcd"}, {Name: "unknown"}},
+				SameHost:             []openstack.ServerReference{{ID: sameID}},
+				BuildNearHostIP:      ptr.To("192.168.1.1/24"),
+				Query:                ptr.To(`[">=", "$free_ram_mb", 1024]`),
+				TargetCell:           ptr.To("cell1"),
+				AdditionalProperties: map[string]string{"reservation": "foo"},
+			}
+			ex := &Executor{
+				Compute: compute,
+				Network: network,
+				Config:  cfg,
+			}
+
+			compute.EXPECT().ListServers(gomock.Any(), &servers.ListOpts{Name: machineName}).Return([]servers.Server{}, nil)
+			compute.EXPECT().ListServers(gomock.Any(), &servers.ListOpts{Name: "etcd"}).Return([]servers.Server{{ID: otherID, Name: "etcd"}, {ID: "other", Name: "etcd-events"}}, nil)
+			compute.EXPECT().ListServers(gomock.Any(), &servers.ListOpts{Name: "unknown"}).Return(nil, nil)
+			compute.EXPECT().ImageIDFromName(gomock.Any(), imageName).Return("imageID", nil)
+			compute.EXPECT().FlavorIDFromName(gomock.Any(), flavorName).Return("flavorID", nil)
+			compute.EXPECT().CreateServer(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, opts servers.CreateOptsBuilder) (*servers.Server, error) {
+				m, err := opts.ToServerCreateMap()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(m["os:scheduler_hints"]).To(Equal(map[string]interface{}{
+					"group":              serverGroupID,
+					"different_host":     []string{otherID},
+					"same_host":          []string{sameID},
+					"build_near_host_ip": "192.168.1.1",
+					"cidr":               "/24",
+					"query":              `["\u003e=","$free_ram_mb",1024]`,
+					"target_cell":        "cell1",
+					"reservation":        "foo",
+				}))
+				return &servers.Server{ID: serverID}, nil
+			})
+			compute.EXPECT().GetServer(gomock.Any(), serverID).Return(&servers.Server{ID: serverID, Status: client.ServerStatusActive}, nil)
+
+			providerId, err := ex.CreateMachine(ctx, machineName, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(providerId).To(Equal(encodeProviderID(region, serverID)))
+		})
+
+		It("should settle on a single managed server group if it was created concurrently", func() {
+			cfg.Spec.ManagedServerGroup = &openstack.ManagedServerGroup{Name: "workers", Policy: "anti-affinity"}
+			ex := &Executor{
+				Compute: compute,
+				Network: network,
+				Config:  cfg,
+			}
+
+			gomock.InOrder(
+				compute.EXPECT().ListServerGroups(gomock.Any(), gomock.Any()).Return(nil, nil),
+				compute.EXPECT().CreateServerGroup(gomock.Any(), gomock.Any()).Return(&servergroups.ServerGroup{ID: "group-b", Name: "foo-foo-workers"}, nil),
+				compute.EXPECT().ListServerGroups(gomock.Any(), gomock.Any()).Return([]servergroups.ServerGroup{
+					{ID: "group-b", Name: "foo-foo-workers", Policies: []string{"anti-affinity"}},
+					{ID: "group-a", Name: "foo-foo-workers", Policies: []string{"anti-affinity"}},
+				}, nil),
+				compute.EXPECT().DeleteServerGroup(gomock.Any(), "group-b").Return(nil),
+			)
+
+			id, err := ex.ensureServerGroup(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(id).To(Equal("group-a"))
+		})
+
+		It("should fail if the managed server group exists with a different policy", func() {
+			cfg.Spec.ManagedServerGroup = &openstack.ManagedServerGroup{Name: "workers", Policy: "anti-affinity"}
+			ex := &Executor{
+				Compute: compute,
+				Network: network,
+				Config:  cfg,
+			}
+
+			compute.EXPECT().ListServerGroups(gomock.Any(), gomock.Any()).Return([]servergroups.ServerGroup{
+				{ID: "groupID", Name: "foo-foo-workers", Policies: []string{"affinity"}},
+			}, nil)
+
+			_, err := ex.ensureServerGroup(ctx)
+			Expect(err).To(HaveOccurred())
+		})
+
+		Context("with an image selector", func() {
+			var ex *Executor
+
+			BeforeEach(func() {
+				cfg.Spec.ImageName = ""
+				cfg.Spec.ImageSelector = &openstack.ImageSelector{
+					Properties:        map[string]string{"os_distro": "gardenlinux"},
+					VersionProperty:   ptr.To("os_version"),
+					VersionConstraint: ptr.To("< 2"),
+				}
+				ex = &Executor{
+					Compute: compute,
+					Network: network,
+					Image:   image,
+					Config:  cfg,
+				}
+				compute.EXPECT().ListServers(gomock.Any(), &servers.ListOpts{Name: machineName}).Return([]servers.Server{}, nil).AnyTimes()
+			})
+
+			It("should select the image with the highest matching version", func() {
+				image.EXPECT().ListImages(gomock.Any(), images.ListOpts{Status: images.ImageStatusActive}).Return([]images.Image{
+					{ID: "old", Properties: map[string]interface{}{"os_distro": "gardenlinux", "os_version": "1.1.0"}},
+					{ID: "new", Properties: map[string]interface{}{"os_distro": "gardenlinux", "os_version": "1.10.0"}},
+					{ID: "major", Properties: map[string]interface{}{"os_distro": "gardenlinux", "os_version": "2.0.0"}},
+					{ID: "other", Properties: map[string]interface{}{"os_distro": "ubuntu", "os_version": "1.20.0"}},
+				}, nil)
+				compute.EXPECT().FlavorIDFromName(gomock.Any(), flavorName).Return("flavorID", nil)
+				compute.EXPECT().CreateServer(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, opts servers.CreateOptsBuilder) (*servers.Server, error) {
+					m, err := opts.ToServerCreateMap()
+					Expect(err).ToNot(HaveOccurred())
+					Expect(m["server"]).To(HaveKeyWithValue("imageRef", "new"))
+					return &servers.Server{ID: serverID}, nil
+				})
+				compute.EXPECT().GetServer(gomock.Any(), serverID).Return(&servers.Server{ID: serverID, Status: client.ServerStatusActive}, nil)
+
+				providerId, err := ex.CreateMachine(ctx, machineName, nil)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(providerId).To(Equal(encodeProviderID(region, serverID)))
+			})
+
+			It("should list the candidates if the selection is ambiguous", func() {
+				image.EXPECT().ListImages(gomock.Any(), gomock.Any()).Return([]images.Image{
+					{ID: "a", Properties: map[string]interface{}{"os_distro": "gardenlinux", "os_version": "1.1.0"}},
+					{ID: "b", Properties: map[string]interface{}{"os_distro": "gardenlinux", "os_version": "1.1.0"}},
+				}, nil)
+
+				_, err := ex.CreateMachine(ctx, machineName, nil)
+				Expect(err).To(MatchError(And(ContainSubstring(`ID="a"`), ContainSubstring(`ID="b"`))))
+			})
+		})
+
 		It("should delete the server on failure", func() {
 			ex := &Executor{
 				Compute: compute,
@@ -208,29 +733,262 @@ var _ = Describe("Executor", func() {
 				Name:     machineName,
 			}
 
-			compute.EXPECT().ListServers(&servers.ListOpts{Name: machineName}).Return([]servers.Server{}, nil)
-			compute.EXPECT().ImageIDFromName(imageName).Return("imageID", nil)
-			compute.EXPECT().FlavorIDFromName(flavorName).Return("flavorID", nil)
-			compute.EXPECT().CreateServer(gomock.Any()).Return(&servers.Server{
+			compute.EXPECT().ListServers(gomock.Any(), &servers.ListOpts{Name: machineName}).Return([]servers.Server{}, nil)
+			compute.EXPECT().ImageIDFromName(gomock.Any(), imageName).Return("imageID", nil)
+			compute.EXPECT().FlavorIDFromName(gomock.Any(), flavorName).Return("flavorID", nil)
+			compute.EXPECT().CreateServer(gomock.Any(), gomock.Any()).Return(&servers.Server{
 				ID: serverID,
 			}, nil)
 
 			gomock.InOrder(
 				// we return an error to avoid waiting for the wait.Poll timeout
-				compute.EXPECT().GetServer(serverID).Return(nil, fmt.Errorf("error fetching server")),
-				compute.EXPECT().ListServers(&servers.ListOpts{Name: machineName}).Return([]servers.Server{*server}, nil),
-				compute.EXPECT().DeleteServer(serverID).Return(nil),
-				compute.EXPECT().GetServer(serverID).Do(func(_ string) { server.Status = client.ServerStatusDeleted }).Return(server, nil),
+				compute.EXPECT().GetServer(gomock.Any(), serverID).Return(nil, fmt.Errorf("error fetching server")),
+				compute.EXPECT().ListServers(gomock.Any(), &servers.ListOpts{Name: machineName}).Return([]servers.Server{*server}, nil),
+				compute.EXPECT().DeleteServer(gomock.Any(), serverID).Return(nil),
+				compute.EXPECT().GetServer(gomock.Any(), serverID).Do(func(_ context.Context, _ string) { server.Status = client.ServerStatusDeleted }).Return(server, nil),
 			)
 
 			_, err := ex.CreateMachine(ctx, machineName, nil)
 			Expect(err).To(HaveOccurred())
 		})
+
+		It("should delete the server if it does not become active within the configured timeout", func() {
+			cfg.Spec.Timeouts = &openstack.Timeouts{
+				ServerCreate: &metav1.Duration{Duration: 50 * time.Millisecond},
+				PollInterval: &metav1.Duration{Duration: 10 * time.Millisecond},
+			}
+			ex := &Executor{
+				Compute: compute,
+				Network: network,
+				Config:  cfg,
+			}
+
+			server := &servers.Server{
+				Metadata: tags,
+				ID:       serverID,
+				Name:     machineName,
+				Status:   client.ServerStatusBuild,
+			}
+
+			compute.EXPECT().ListServers(gomock.Any(), &servers.ListOpts{Name: machineName}).Return([]servers.Server{}, nil)
+			compute.EXPECT().ImageIDFromName(gomock.Any(), imageName).Return("imageID", nil)
+			compute.EXPECT().FlavorIDFromName(gomock.Any(), flavorName).Return("flavorID", nil)
+			compute.EXPECT().CreateServer(gomock.Any(), gomock.Any()).Return(&servers.Server{ID: serverID}, nil)
+			// the server keeps building until it is deleted after the timeout expired
+			compute.EXPECT().GetServer(gomock.Any(), serverID).Return(server, nil).MinTimes(3)
+			gomock.InOrder(
+				compute.EXPECT().ListServers(gomock.Any(), &servers.ListOpts{Name: machineName}).Return([]servers.Server{*server}, nil),
+				compute.EXPECT().DeleteServer(gomock.Any(), serverID).Do(func(_ context.Context, _ string) { server.Status = client.ServerStatusDeleted }).Return(nil),
+			)
+
+			_, err := ex.CreateMachine(ctx, machineName, nil)
+			Expect(err).To(MatchError(context.DeadlineExceeded))
+		})
+	})
+
+	Context("Initialize", func() {
+		var (
+			serverID = "server"
+			portID   = "portID"
+			podCidr  = "10.0.0.0/16"
+		)
+		BeforeEach(func() {
+			cfg.Spec.PodNetworkCidr = podCidr
+		})
+
+		It("should patch the ports and metadata of the server", func() {
+			ex := &Executor{
+				Compute: compute,
+				Network: network,
+				Config:  cfg,
+			}
+
+			compute.EXPECT().GetServer(gomock.Any(), serverID).Return(&servers.Server{
+				ID:       serverID,
+				Status:   client.ServerStatusActive,
+				Metadata: tags,
+			}, nil)
+			network.EXPECT().ListPorts(gomock.Any(), &ports.ListOpts{DeviceID: serverID}).Return([]ports.Port{{NetworkID: networkID, ID: portID}}, nil)
+			network.EXPECT().UpdatePort(gomock.Any(), portID, ports.UpdateOpts{
+				AllowedAddressPairs: &[]ports.AddressPair{{IPAddress: podCidr}},
+			}).Return(nil)
+
+			providerId, err := ex.InitializeMachine(ctx, "", encodeProviderID(region, serverID))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(providerId).To(Equal(encodeProviderID(region, serverID)))
+		})
+
+		It("should keep existing address pairs and update only missing metadata", func() {
+			ex := &Executor{
+				Compute: compute,
+				Network: network,
+				Config:  cfg,
+			}
+
+			cfg.Spec.Tags["foo"] = "bar"
+			compute.EXPECT().GetServer(gomock.Any(), serverID).Return(&servers.Server{
+				ID:     serverID,
+				Status: client.ServerStatusActive,
+				Metadata: map[string]string{
+					fmt.Sprintf("%sfoo", cloudprovider.ServerTagClusterPrefix): "1",
+					fmt.Sprintf("%sfoo", cloudprovider.ServerTagRolePrefix):    "1",
+				},
+			}, nil)
+			network.EXPECT().ListPorts(gomock.Any(), &ports.ListOpts{DeviceID: serverID}).Return([]ports.Port{{
+				NetworkID:           networkID,
+				ID:                  portID,
+				AllowedAddressPairs: []ports.AddressPair{{IPAddress: "192.168.0.0/24"}},
+			}}, nil)
+			network.EXPECT().UpdatePort(gomock.Any(), portID, ports.UpdateOpts{
+				AllowedAddressPairs: &[]ports.AddressPair{{IPAddress: "192.168.0.0/24"}, {IPAddress: podCidr}},
+			}).Return(nil)
+			compute.EXPECT().UpdateServerMetadata(gomock.Any(), serverID, servers.MetadataOpts{"foo": "bar"}).Return(nil)
+
+			_, err := ex.InitializeMachine(ctx, "", encodeProviderID(region, serverID))
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should allocate and associate a floating IP", func() {
+			ex := &Executor{
+				Compute: compute,
+				Network: network,
+				Config:  cfg,
+			}
+
+			cfg.Spec.FloatingIP = &openstack.FloatingIP{PoolName: "public"}
+			server := &servers.Server{
+				ID:       serverID,
+				Name:     "name",
+				Status:   client.ServerStatusActive,
+				Metadata: tags,
+			}
+			compute.EXPECT().GetServer(gomock.Any(), serverID).Return(server, nil)
+			network.EXPECT().ListPorts(gomock.Any(), &ports.ListOpts{DeviceID: serverID}).Return([]ports.Port{{
+				NetworkID:           networkID,
+				ID:                  portID,
+				AllowedAddressPairs: []ports.AddressPair{{IPAddress: podCidr}},
+			}}, nil)
+			network.EXPECT().ListPorts(gomock.Any(), &ports.ListOpts{DeviceID: serverID, NetworkID: networkID}).Return([]ports.Port{{NetworkID: networkID, ID: portID}}, nil)
+			network.EXPECT().ListFloatingIPs(gomock.Any(), floatingips.ListOpts{PortID: portID}).Return(nil, nil)
+			network.EXPECT().NetworkIDFromName(gomock.Any(), "public").Return("publicID", nil)
+			network.EXPECT().CreateFloatingIP(gomock.Any(), floatingips.CreateOpts{
+				FloatingNetworkID: "publicID",
+				PortID:            portID,
+				Description:       server.Name,
+			}).Return(&floatingips.FloatingIP{ID: "fip"}, nil)
+			network.EXPECT().TagFloatingIP(gomock.Any(), "fip", gomock.Len(2)).Return(nil)
+
+			_, err := ex.InitializeMachine(ctx, "", encodeProviderID(region, serverID))
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should delete the allocated floating IP if it cannot be tagged", func() {
+			ex := &Executor{
+				Compute: compute,
+				Network: network,
+				Config:  cfg,
+			}
+
+			cfg.Spec.FloatingIP = &openstack.FloatingIP{NetworkID: "publicID"}
+			server := &servers.Server{
+				ID:       serverID,
+				Name:     "name",
+				Status:   client.ServerStatusActive,
+				Metadata: tags,
+			}
+			compute.EXPECT().GetServer(gomock.Any(), serverID).Return(server, nil)
+			network.EXPECT().ListPorts(gomock.Any(), &ports.ListOpts{DeviceID: serverID}).Return([]ports.Port{{
+				NetworkID:           networkID,
+				ID:                  portID,
+				AllowedAddressPairs: []ports.AddressPair{{IPAddress: podCidr}},
+			}}, nil)
+			network.EXPECT().ListPorts(gomock.Any(), &ports.ListOpts{DeviceID: serverID, NetworkID: networkID}).Return([]ports.Port{{NetworkID: networkID, ID: portID}}, nil)
+			network.EXPECT().ListFloatingIPs(gomock.Any(), floatingips.ListOpts{PortID: portID}).Return(nil, nil)
+			gomock.InOrder(
+				network.EXPECT().CreateFloatingIP(gomock.Any(), gomock.Any()).Return(&floatingips.FloatingIP{ID: "fip"}, nil),
+				network.EXPECT().TagFloatingIP(gomock.Any(), "fip", gomock.Len(2)).Return(fmt.Errorf("conflict")),
+				network.EXPECT().DeleteFloatingIP(gomock.Any(), "fip").Return(nil),
+			)
+
+			_, err := ex.InitializeMachine(ctx, "", encodeProviderID(region, serverID))
+			Expect(err).To(MatchError(ContainSubstring("failed to tag floating IP")))
+		})
+
+		It("should reuse an unassociated floating IP", func() {
+			ex := &Executor{
+				Compute: compute,
+				Network: network,
+				Config:  cfg,
+			}
+
+			cfg.Spec.FloatingIP = &openstack.FloatingIP{NetworkID: "publicID", ReuseUnassociated: true}
+			server := &servers.Server{
+				ID:       serverID,
+				Name:     "name",
+				Status:   client.ServerStatusActive,
+				Metadata: tags,
+			}
+			compute.EXPECT().GetServer(gomock.Any(), serverID).Return(server, nil)
+			network.EXPECT().ListPorts(gomock.Any(), &ports.ListOpts{DeviceID: serverID}).Return([]ports.Port{{
+				NetworkID:           networkID,
+				ID:                  portID,
+				AllowedAddressPairs: []ports.AddressPair{{IPAddress: podCidr}},
+			}}, nil)
+			network.EXPECT().ListPorts(gomock.Any(), &ports.ListOpts{DeviceID: serverID, NetworkID: networkID}).Return([]ports.Port{{NetworkID: networkID, ID: portID}}, nil)
+			network.EXPECT().ListFloatingIPs(gomock.Any(), floatingips.ListOpts{PortID: portID}).Return(nil, nil)
+			network.EXPECT().ListFloatingIPs(gomock.Any(), gomock.Any()).Return([]floatingips.FloatingIP{
+				{ID: "used", PortID: "other"},
+				{ID: "free"},
+			}, nil)
+			network.EXPECT().UpdateFloatingIP(gomock.Any(), "free", floatingips.UpdateOpts{
+				PortID:      ptr.To(portID),
+				Description: ptr.To(server.Name),
+			}).Return(nil)
+
+			_, err := ex.InitializeMachine(ctx, "", encodeProviderID(region, serverID))
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should not delete the server on failure", func() {
+			ex := &Executor{
+				Compute: compute,
+				Network: network,
+				Config:  cfg,
+			}
+
+			compute.EXPECT().GetServer(gomock.Any(), serverID).Return(&servers.Server{
+				ID:       serverID,
+				Status:   client.ServerStatusActive,
+				Metadata: tags,
+			}, nil)
+			network.EXPECT().ListPorts(gomock.Any(), &ports.ListOpts{DeviceID: serverID}).Return([]ports.Port{{NetworkID: networkID, ID: portID}}, nil)
+			network.EXPECT().UpdatePort(gomock.Any(), portID, gomock.Any()).Return(fmt.Errorf("conflict"))
+
+			_, err := ex.InitializeMachine(ctx, "", encodeProviderID(region, serverID))
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should fail if the server is not active", func() {
+			ex := &Executor{
+				Compute: compute,
+				Network: network,
+				Config:  cfg,
+			}
+
+			compute.EXPECT().GetServer(gomock.Any(), serverID).Return(&servers.Server{
+				ID:       serverID,
+				Status:   client.ServerStatusBuild,
+				Metadata: tags,
+			}, nil)
+
+			_, err := ex.InitializeMachine(ctx, "", encodeProviderID(region, serverID))
+			Expect(err).To(HaveOccurred())
+		})
 	})
 
 	Context("List", func() {
 		It("should filter the instances based on tags", func() {
-			compute.EXPECT().ListServers(gomock.Any()).Return(
+			compute.EXPECT().ListServers(gomock.Any(), gomock.Any()).Return(
 				[]servers.Server{
 					{
 						Metadata: tags,
@@ -263,6 +1021,102 @@ var _ = Describe("Executor", func() {
 				encodeProviderID(region, "id2"): "bar",
 			}))
 		})
+
+		Context("server tags are supported", func() {
+			var ownershipTags []string
+
+			BeforeEach(func() {
+				serverTags = true
+				ownershipTags = []string{
+					fmt.Sprintf("%sfoo", cloudprovider.ServerTagClusterPrefix),
+					fmt.Sprintf("%sfoo", cloudprovider.ServerTagRolePrefix),
+				}
+				migratedServerTags = &serverTagMigration{done: sets.New[string]()}
+			})
+
+			It("should tag the instances which are only marked by their metadata and filter server-side afterwards", func() {
+				gomock.InOrder(
+					compute.EXPECT().ListServers(gomock.Any(), &servers.ListOpts{}).Return(
+						[]servers.Server{
+							{
+								Tags: &ownershipTags,
+								ID:   "id1",
+								Name: "foo",
+							},
+							{
+								Metadata: tags,
+								ID:       "id2",
+								Name:     "bar",
+							},
+							{
+								ID:   "id3",
+								Name: "baz",
+							},
+						},
+						nil),
+					compute.EXPECT().AddServerTags(gomock.Any(), "id2", ownershipTags).Return(nil),
+					compute.EXPECT().ListServers(gomock.Any(), &servers.ListOpts{Tags: strings.Join(ownershipTags, ",")}).Return(
+						[]servers.Server{
+							{
+								Tags: &ownershipTags,
+								ID:   "id1",
+								Name: "foo",
+							},
+							{
+								Tags:     &ownershipTags,
+								Metadata: tags,
+								ID:       "id2",
+								Name:     "bar",
+							},
+						},
+						nil),
+				)
+
+				ex := Executor{
+					Compute: compute,
+					Network: network,
+					Config:  cfg,
+				}
+
+				expected := map[string]string{
+					encodeProviderID(region, "id1"): "foo",
+					encodeProviderID(region, "id2"): "bar",
+				}
+				res, err := ex.ListMachines(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(res).To(Equal(expected))
+
+				res, err = ex.ListMachines(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(res).To(Equal(expected))
+			})
+
+			It("should keep listing all instances until they are tagged", func() {
+				untagged := []servers.Server{{
+					Metadata: tags,
+					ID:       "id1",
+					Name:     "foo",
+				}}
+				gomock.InOrder(
+					compute.EXPECT().ListServers(gomock.Any(), &servers.ListOpts{}).Return(untagged, nil),
+					compute.EXPECT().AddServerTags(gomock.Any(), "id1", ownershipTags).Return(fmt.Errorf("conflict")),
+					compute.EXPECT().ListServers(gomock.Any(), &servers.ListOpts{}).Return(untagged, nil),
+					compute.EXPECT().AddServerTags(gomock.Any(), "id1", ownershipTags).Return(nil),
+				)
+
+				ex := Executor{
+					Compute: compute,
+					Network: network,
+					Config:  cfg,
+				}
+
+				for range 2 {
+					res, err := ex.ListMachines(ctx)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(res).To(Equal(map[string]string{encodeProviderID(region, "id1"): "foo"}))
+				}
+			})
+		})
 	})
 
 	Context("#GetMachineStatus", func() {
@@ -298,12 +1152,20 @@ var _ = Describe("Executor", func() {
 					Name:     "lorem",
 					Metadata: tags,
 				},
+				{
+					ID:   "id7",
+					Name: "tagged",
+					Tags: &[]string{
+						fmt.Sprintf("%sfoo", cloudprovider.ServerTagClusterPrefix),
+						fmt.Sprintf("%sfoo", cloudprovider.ServerTagRolePrefix),
+					},
+				},
 			}
 		})
 
 		DescribeTable("#Status",
 			func(name string, expectedID string, expectedErr error) {
-				compute.EXPECT().ListServers(&servers.ListOpts{Name: name}).Return(serverList, nil)
+				compute.EXPECT().ListServers(gomock.Any(), &servers.ListOpts{Name: name}).Return(serverList, nil)
 				ex := Executor{
 					Compute: compute,
 					Network: network,
@@ -318,10 +1180,80 @@ var _ = Describe("Executor", func() {
 				}
 			},
 			Entry("Should find the entry with matching metadata", "foo", "id1", nil),
+			Entry("Should find the entry with matching server tags", "tagged", "id7", nil),
 			Entry("Should return not found if name not exists", "unknown", "", ErrNotFound),
 			Entry("Should return not found if name exists without matching metadata", "baz", "", ErrNotFound),
 			Entry("Should detect multiple matching servers", "lorem", "", ErrMultipleFound),
 		)
+
+		DescribeTable("#GetMachineStatus",
+			func(serverStatus string, pairs []ports.AddressPair, expectedErr error) {
+				id := "id"
+				cfg.Spec.PodNetworkCIDRs = []string{"10.0.0.0/16"}
+				compute.EXPECT().GetServer(gomock.Any(), id).Return(&servers.Server{ID: id, Status: serverStatus, Metadata: tags}, nil)
+				if serverStatus == client.ServerStatusActive {
+					network.EXPECT().ListPorts(gomock.Any(), &ports.ListOpts{DeviceID: id}).Return([]ports.Port{{NetworkID: networkID, ID: "port", AllowedAddressPairs: pairs}}, nil)
+				}
+				ex := Executor{
+					Compute: compute,
+					Network: network,
+					Config:  cfg,
+				}
+				providerID, err := ex.GetMachineStatus(ctx, "", encodeProviderID(region, id))
+				if expectedErr != nil {
+					Expect(errors.Is(err, expectedErr)).To(BeTrue())
+				} else {
+					Expect(err).ToNot(HaveOccurred())
+					Expect(providerID).To(Equal(encodeProviderID(region, id)))
+				}
+			},
+			Entry("Should return the providerID of an active server", client.ServerStatusActive, []ports.AddressPair{{IPAddress: "10.0.0.0/16"}}, nil),
+			Entry("Should report an active server with unpatched ports as not initialized", client.ServerStatusActive, nil, ErrNotInitialized),
+			Entry("Should report a building server as not initialized", client.ServerStatusBuild, nil, ErrNotInitialized),
+			Entry("Should report a server in error status", client.ServerStatusError, nil, ErrServerFailed),
+			Entry("Should report a stopped server", client.ServerStatusShutoff, nil, ErrServerShutoff),
+		)
+
+		It("should tag servers which are only marked by their metadata", func() {
+			id := "id"
+			serverTags = true
+			compute.EXPECT().GetServer(gomock.Any(), id).Return(&servers.Server{ID: id, Status: client.ServerStatusBuild, Metadata: tags}, nil)
+			compute.EXPECT().AddServerTags(gomock.Any(), id, []string{
+				fmt.Sprintf("%sfoo", cloudprovider.ServerTagClusterPrefix),
+				fmt.Sprintf("%sfoo", cloudprovider.ServerTagRolePrefix),
+			}).Return(fmt.Errorf("conflict"))
+			ex := Executor{
+				Compute: compute,
+				Network: network,
+				Config:  cfg,
+			}
+			_, err := ex.GetMachineStatus(ctx, "", encodeProviderID(region, id))
+			Expect(errors.Is(err, ErrNotInitialized)).To(BeTrue())
+		})
+
+		It("should return not found if the server is gone", func() {
+			id := "id"
+			compute.EXPECT().GetServer(gomock.Any(), id).Return(nil, gophercloud.ErrDefault404{})
+			ex := Executor{
+				Compute: compute,
+				Network: network,
+				Config:  cfg,
+			}
+			_, err := ex.GetMachineStatus(ctx, "", encodeProviderID(region, id))
+			Expect(errors.Is(err, ErrNotFound)).To(BeTrue())
+		})
+
+		It("should fall back to the machine name if no providerID is supplied", func() {
+			compute.EXPECT().ListServers(gomock.Any(), &servers.ListOpts{Name: "foo"}).Return(serverList, nil)
+			ex := Executor{
+				Compute: compute,
+				Network: network,
+				Config:  cfg,
+			}
+			providerID, err := ex.GetMachineStatus(ctx, "foo", "")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(providerID).To(Equal(encodeProviderID(region, "id1")))
+		})
 	})
 
 	Context("Delete", func() {
@@ -342,7 +1274,7 @@ var _ = Describe("Executor", func() {
 		})
 
 		It("should return no error if NotFound", func() {
-			compute.EXPECT().ListServers(&servers.ListOpts{Name: "unknown"}).Return(serverList, nil)
+			compute.EXPECT().ListServers(gomock.Any(), &servers.ListOpts{Name: "unknown"}).Return(serverList, nil)
 			ex := Executor{
 				Compute: compute,
 				Network: network,
@@ -353,9 +1285,9 @@ var _ = Describe("Executor", func() {
 		})
 
 		It("should return no error if delete is successful", func() {
-			compute.EXPECT().ListServers(&servers.ListOpts{Name: "foo"}).Return(serverList, nil)
-			compute.EXPECT().DeleteServer("id1").Return(nil)
-			compute.EXPECT().GetServer("id1").Return(&servers.Server{Status: client.ServerStatusDeleted}, nil)
+			compute.EXPECT().ListServers(gomock.Any(), &servers.ListOpts{Name: "foo"}).Return(serverList, nil)
+			compute.EXPECT().DeleteServer(gomock.Any(), "id1").Return(nil)
+			compute.EXPECT().GetServer(gomock.Any(), "id1").Return(&servers.Server{Status: client.ServerStatusDeleted}, nil)
 			ex := Executor{
 				Compute: compute,
 				Network: network,
@@ -365,12 +1297,44 @@ var _ = Describe("Executor", func() {
 			Expect(err).ToNot(HaveOccurred())
 		})
 
+		It("should stop waiting for the deletion if the context is cancelled", func() {
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+
+			compute.EXPECT().ListServers(ctx, &servers.ListOpts{Name: "foo"}).Return(serverList, nil)
+			compute.EXPECT().DeleteServer(ctx, "id1").Return(nil)
+			compute.EXPECT().GetServer(gomock.Any(), "id1").DoAndReturn(func(_ context.Context, id string) (*servers.Server, error) {
+				cancel()
+				return &servers.Server{ID: id, Status: client.ServerStatusActive}, nil
+			})
+			ex := Executor{
+				Compute: compute,
+				Network: network,
+				Config:  cfg,
+			}
+			err := ex.DeleteMachine(ctx, "foo", "")
+			Expect(err).To(MatchError(context.Canceled))
+		})
+
+		It("should keep the request ID of a failed deletion", func() {
+			compute.EXPECT().ListServers(gomock.Any(), &servers.ListOpts{Name: "foo"}).Return(serverList, nil)
+			compute.EXPECT().DeleteServer(gomock.Any(), "id1").Return(&client.RequestError{RequestID: "req-foo", Err: gophercloud.ErrDefault500{}})
+			ex := Executor{
+				Compute: compute,
+				Network: network,
+				Config:  cfg,
+			}
+			err := ex.DeleteMachine(ctx, "foo", "")
+			Expect(client.RequestID(err)).To(Equal("req-foo"))
+			Expect(err.Error()).To(ContainSubstring("request ID: req-foo"))
+		})
+
 		It("should try to find by ProviderID if supplied", func() {
 			id := "id"
 			gomock.InOrder(
-				compute.EXPECT().GetServer(id).Return(&servers.Server{ID: id, Status: client.ServerStatusActive, Metadata: tags}, nil),
-				compute.EXPECT().DeleteServer(id).Return(nil),
-				compute.EXPECT().GetServer(id).Return(&servers.Server{ID: id, Status: client.ServerStatusDeleted, Metadata: tags}, nil),
+				compute.EXPECT().GetServer(gomock.Any(), id).Return(&servers.Server{ID: id, Status: client.ServerStatusActive, Metadata: tags}, nil),
+				compute.EXPECT().DeleteServer(gomock.Any(), id).Return(nil),
+				compute.EXPECT().GetServer(gomock.Any(), id).Return(&servers.Server{ID: id, Status: client.ServerStatusDeleted, Metadata: tags}, nil),
 			)
 			ex := Executor{
 				Compute: compute,
@@ -390,13 +1354,130 @@ var _ = Describe("Executor", func() {
 
 			cfg.Spec.SubnetID = pointer.StringPtr(subnetID)
 			gomock.InOrder(
-				compute.EXPECT().ListServers(&servers.ListOpts{Name: machineName}).Return(serverList, nil),
-				compute.EXPECT().DeleteServer("id1").Return(nil),
-				compute.EXPECT().GetServer("id1").Return(&servers.Server{Status: client.ServerStatusDeleted}, nil),
+				compute.EXPECT().ListServers(gomock.Any(), &servers.ListOpts{Name: machineName}).Return(serverList, nil),
+				compute.EXPECT().DeleteServer(gomock.Any(), "id1").Return(nil),
+				compute.EXPECT().GetServer(gomock.Any(), "id1").Return(&servers.Server{Status: client.ServerStatusDeleted}, nil),
 			)
 			gomock.InOrder(
-				network.EXPECT().ListPorts(ports.ListOpts{Name: machineName}).Return([]ports.Port{{ID: portID}}, nil),
-				network.EXPECT().DeletePort(portID).Return(nil),
+				network.EXPECT().ListPorts(gomock.Any(), ports.ListOpts{Name: machineName}).Return([]ports.Port{{ID: portID}}, nil),
+				network.EXPECT().DeletePort(gomock.Any(), portID).Return(nil),
+			)
+
+			ex := Executor{
+				Compute: compute,
+				Network: network,
+				Config:  cfg,
+			}
+			err := ex.DeleteMachine(ctx, machineName, "")
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should release the floating IPs of the machine", func() {
+			machineName := "foo"
+
+			cfg.Spec.FloatingIP = &openstack.FloatingIP{PoolName: "public"}
+			gomock.InOrder(
+				compute.EXPECT().ListServers(gomock.Any(), &servers.ListOpts{Name: machineName}).Return(serverList, nil),
+				compute.EXPECT().DeleteServer(gomock.Any(), "id1").Return(nil),
+				compute.EXPECT().GetServer(gomock.Any(), "id1").Return(&servers.Server{Status: client.ServerStatusDeleted}, nil),
+				network.EXPECT().ListFloatingIPs(gomock.Any(), gomock.Any()).Return([]floatingips.FloatingIP{{ID: "fip"}}, nil),
+				network.EXPECT().DeleteFloatingIP(gomock.Any(), "fip").Return(nil),
+			)
+
+			ex := Executor{
+				Compute: compute,
+				Network: network,
+				Config:  cfg,
+			}
+			err := ex.DeleteMachine(ctx, machineName, "")
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should only disassociate reusable floating IPs", func() {
+			machineName := "foo"
+
+			cfg.Spec.FloatingIP = &openstack.FloatingIP{PoolName: "public", ReuseUnassociated: true}
+			gomock.InOrder(
+				compute.EXPECT().ListServers(gomock.Any(), &servers.ListOpts{Name: machineName}).Return(serverList, nil),
+				compute.EXPECT().DeleteServer(gomock.Any(), "id1").Return(nil),
+				compute.EXPECT().GetServer(gomock.Any(), "id1").Return(&servers.Server{Status: client.ServerStatusDeleted}, nil),
+				network.EXPECT().ListFloatingIPs(gomock.Any(), gomock.Any()).Return([]floatingips.FloatingIP{{ID: "fip"}}, nil),
+				network.EXPECT().UpdateFloatingIP(gomock.Any(), "fip", floatingips.UpdateOpts{
+					PortID:      ptr.To(""),
+					Description: ptr.To(""),
+				}).Return(nil),
+			)
+
+			ex := Executor{
+				Compute: compute,
+				Network: network,
+				Config:  cfg,
+			}
+			err := ex.DeleteMachine(ctx, machineName, "")
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should delete the data volumes of the machine", func() {
+			machineName := "foo"
+			cfg.Spec.DataVolumes = []openstack.DataVolume{
+				{Name: "etcd", Size: 10},
+				{Name: "data", Size: 10, DeleteOnTermination: ptr.To(false)},
+			}
+			gomock.InOrder(
+				compute.EXPECT().ListServers(gomock.Any(), &servers.ListOpts{Name: machineName}).Return(serverList, nil),
+				compute.EXPECT().DeleteServer(gomock.Any(), "id1").Return(nil),
+				compute.EXPECT().GetServer(gomock.Any(), "id1").Return(&servers.Server{Status: client.ServerStatusDeleted}, nil),
+			)
+			gomock.InOrder(
+				storage.EXPECT().VolumeIDFromName(gomock.Any(), machineName+"-etcd").Return("volumeID", nil),
+				storage.EXPECT().GetVolume(gomock.Any(), "volumeID").Return(&volumes.Volume{ID: "volumeID", Status: client.VolumeStatusAvailable}, nil),
+				storage.EXPECT().DeleteVolume(gomock.Any(), "volumeID").Return(nil),
+				storage.EXPECT().GetVolume(gomock.Any(), "volumeID").Return(nil, gophercloud.ErrResourceNotFound{}),
+			)
+
+			ex := Executor{
+				Compute: compute,
+				Network: network,
+				Storage: storage,
+				Config:  cfg,
+			}
+			err := ex.DeleteMachine(ctx, machineName, "")
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should delete the managed server group once it is empty", func() {
+			machineName := "foo"
+			cfg.Spec.ManagedServerGroup = &openstack.ManagedServerGroup{Name: "workers", Policy: "anti-affinity"}
+			gomock.InOrder(
+				compute.EXPECT().ListServers(gomock.Any(), &servers.ListOpts{Name: machineName}).Return(serverList, nil),
+				compute.EXPECT().DeleteServer(gomock.Any(), "id1").Return(nil),
+				compute.EXPECT().GetServer(gomock.Any(), "id1").Return(&servers.Server{Status: client.ServerStatusDeleted}, nil),
+				compute.EXPECT().ListServerGroups(gomock.Any(), gomock.Any()).Return([]servergroups.ServerGroup{
+					{ID: "groupID", Name: "foo-foo-workers"},
+					{ID: "otherID", Name: "foo-foo-masters"},
+				}, nil),
+				compute.EXPECT().DeleteServerGroup(gomock.Any(), "groupID").Return(nil),
+			)
+
+			ex := Executor{
+				Compute: compute,
+				Network: network,
+				Config:  cfg,
+			}
+			err := ex.DeleteMachine(ctx, machineName, "")
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should keep the managed server group while it has members", func() {
+			machineName := "foo"
+			cfg.Spec.ManagedServerGroup = &openstack.ManagedServerGroup{Name: "workers", Policy: "anti-affinity"}
+			gomock.InOrder(
+				compute.EXPECT().ListServers(gomock.Any(), &servers.ListOpts{Name: machineName}).Return(serverList, nil),
+				compute.EXPECT().DeleteServer(gomock.Any(), "id1").Return(nil),
+				compute.EXPECT().GetServer(gomock.Any(), "id1").Return(&servers.Server{Status: client.ServerStatusDeleted}, nil),
+				compute.EXPECT().ListServerGroups(gomock.Any(), gomock.Any()).Return([]servergroups.ServerGroup{
+					{ID: "groupID", Name: "foo-foo-workers", Members: []string{"id3"}},
+				}, nil),
 			)
 
 			ex := Executor{
@@ -418,14 +1499,14 @@ var _ = Describe("Executor", func() {
 
 			cfg.Spec.SubnetID = pointer.StringPtr(subnetID)
 			gomock.InOrder(
-				compute.EXPECT().ListServers(&servers.ListOpts{Name: machineName}).Return(serverList, nil),
-				compute.EXPECT().DeleteServer("id1").Return(nil),
-				compute.EXPECT().GetServer("id1").Return(&servers.Server{Status: client.ServerStatusDeleted}, nil),
+				compute.EXPECT().ListServers(gomock.Any(), &servers.ListOpts{Name: machineName}).Return(serverList, nil),
+				compute.EXPECT().DeleteServer(gomock.Any(), "id1").Return(nil),
+				compute.EXPECT().GetServer(gomock.Any(), "id1").Return(&servers.Server{Status: client.ServerStatusDeleted}, nil),
 			)
 			gomock.InOrder(
-				network.EXPECT().ListPorts(ports.ListOpts{Name: machineName}).Return([]ports.Port{{ID: portID1}, {ID: portID2}}, nil),
-				network.EXPECT().DeletePort(portID1).Return(nil),
-				network.EXPECT().DeletePort(portID2).Return(nil),
+				network.EXPECT().ListPorts(gomock.Any(), ports.ListOpts{Name: machineName}).Return([]ports.Port{{ID: portID1}, {ID: portID2}}, nil),
+				network.EXPECT().DeletePort(gomock.Any(), portID1).Return(nil),
+				network.EXPECT().DeletePort(gomock.Any(), portID2).Return(nil),
 			)
 
 			ex := Executor{
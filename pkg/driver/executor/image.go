@@ -0,0 +1,132 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package executor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/gophercloud/gophercloud/openstack/imageservice/v2/images"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+
+	api "github.com/gardener/machine-controller-manager-provider-openstack/pkg/apis/openstack"
+)
+
+// selectImage resolves the ID of the image matching the selector. If multiple images match, the image with the highest
+// version is selected if the selector specifies a version property, otherwise the newest image is selected.
+func (ex *Executor) selectImage(ctx context.Context, selector *api.ImageSelector) (string, error) {
+	list, err := ex.Image.ListImages(ctx, images.ListOpts{
+		Name:       ptr.Deref(selector.Name, ""),
+		Tags:       selector.Tags,
+		Visibility: images.ImageVisibility(ptr.Deref(selector.Visibility, "")),
+		Status:     images.ImageStatusActive,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var candidates []images.Image
+	for _, image := range list {
+		if hasImageProperties(image, selector.Properties) {
+			candidates = append(candidates, image)
+		}
+	}
+
+	var selected []images.Image
+	if selector.VersionProperty != nil {
+		selected, err = highestVersionImages(candidates, *selector.VersionProperty, selector.VersionConstraint)
+		if err != nil {
+			return "", err
+		}
+	} else {
+		selected = newestImages(candidates)
+	}
+
+	switch len(selected) {
+	case 0:
+		return "", fmt.Errorf("no image matches the image selector")
+	case 1:
+		klog.V(3).Infof("selected image [Name=%q, ID=%q]", selected[0].Name, selected[0].ID)
+		return selected[0].ID, nil
+	default:
+		return "", fmt.Errorf("image selector is ambiguous, candidates are %s: %w", describeImages(selected), ErrMultipleFound)
+	}
+}
+
+// hasImageProperties returns true if the image has all the given properties with matching values.
+func hasImageProperties(image images.Image, properties map[string]string) bool {
+	for k, v := range properties {
+		value, ok := image.Properties[k]
+		if !ok || fmt.Sprint(value) != v {
+			return false
+		}
+	}
+	return true
+}
+
+// newestImages returns the images with the most recent creation timestamp.
+func newestImages(candidates []images.Image) []images.Image {
+	var newest []images.Image
+	for _, image := range candidates {
+		switch {
+		case len(newest) == 0 || image.CreatedAt.After(newest[0].CreatedAt):
+			newest = []images.Image{image}
+		case image.CreatedAt.Equal(newest[0].CreatedAt):
+			newest = append(newest, image)
+		}
+	}
+	return newest
+}
+
+// highestVersionImages returns the images with the highest semantic version stored in the versionProperty. Images without
+// a valid version, or with a version not satisfying the constraint, are skipped.
+func highestVersionImages(candidates []images.Image, versionProperty string, versionConstraint *string) ([]images.Image, error) {
+	var constraint *semver.Constraints
+	if versionConstraint != nil {
+		c, err := semver.NewConstraint(*versionConstraint)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version constraint %q: %w", *versionConstraint, err)
+		}
+		constraint = c
+	}
+
+	var (
+		highest        []images.Image
+		highestVersion *semver.Version
+	)
+	for _, image := range candidates {
+		value, ok := image.Properties[versionProperty]
+		if !ok {
+			continue
+		}
+		version, err := semver.NewVersion(fmt.Sprint(value))
+		if err != nil {
+			klog.V(3).Infof("skipping image [Name=%q, ID=%q] with invalid version %q: %v", image.Name, image.ID, value, err)
+			continue
+		}
+		if constraint != nil && !constraint.Check(version) {
+			continue
+		}
+
+		switch {
+		case highestVersion == nil || version.GreaterThan(highestVersion):
+			highest, highestVersion = []images.Image{image}, version
+		case version.Equal(highestVersion):
+			highest = append(highest, image)
+		}
+	}
+	return highest, nil
+}
+
+func describeImages(list []images.Image) string {
+	descriptions := make([]string, 0, len(list))
+	for _, image := range list {
+		descriptions = append(descriptions, fmt.Sprintf("[Name=%q, ID=%q, CreatedAt=%s]", image.Name, image.ID, image.CreatedAt))
+	}
+	return strings.Join(descriptions, ", ")
+}
@@ -0,0 +1,95 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/schedulerhints"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+
+	api "github.com/gardener/machine-controller-manager-provider-openstack/pkg/apis/openstack"
+)
+
+// schedulerHints returns the scheduler hints for the server, combining the server group with the hints of the provider
+// spec. It returns nil if no hint is set.
+func (ex *Executor) schedulerHints(ctx context.Context, serverGroupID string) (*schedulerhints.SchedulerHints, error) {
+	config := ex.Config.Spec.SchedulerHints
+	if config == nil {
+		if serverGroupID == "" {
+			return nil, nil
+		}
+		return &schedulerhints.SchedulerHints{Group: serverGroupID}, nil
+	}
+
+	differentHost, err := ex.resolveServerReferences(ctx, config.DifferentHost, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve different host servers: %w", err)
+	}
+	sameHost, err := ex.resolveServerReferences(ctx, config.SameHost, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve same host servers: %w", err)
+	}
+
+	var query []interface{}
+	if config.Query != nil {
+		if err := json.Unmarshal([]byte(*config.Query), &query); err != nil {
+			return nil, fmt.Errorf("failed to decode query %q: %w", *config.Query, err)
+		}
+	}
+
+	var additionalProperties map[string]interface{}
+	if len(config.AdditionalProperties) > 0 {
+		additionalProperties = make(map[string]interface{}, len(config.AdditionalProperties))
+		for k, v := range config.AdditionalProperties {
+			additionalProperties[k] = v
+		}
+	}
+
+	return &schedulerhints.SchedulerHints{
+		Group:                serverGroupID,
+		DifferentHost:        differentHost,
+		SameHost:             sameHost,
+		Query:                query,
+		TargetCell:           ptr.Deref(config.TargetCell, ""),
+		BuildNearHostIP:      ptr.Deref(config.BuildNearHostIP, ""),
+		AdditionalProperties: additionalProperties,
+	}, nil
+}
+
+// resolveServerReferences resolves the references to server IDs. A name references all servers with that name. If
+// required is set, a name without any matching server results in an ErrNotFound error, otherwise it is skipped.
+func (ex *Executor) resolveServerReferences(ctx context.Context, references []api.ServerReference, required bool) ([]string, error) {
+	var ids []string
+	for _, reference := range references {
+		if reference.ID != "" {
+			ids = append(ids, reference.ID)
+			continue
+		}
+
+		listedServers, err := ex.Compute.ListServers(ctx, &servers.ListOpts{Name: reference.Name})
+		if err != nil {
+			return nil, err
+		}
+		found := false
+		for _, server := range listedServers {
+			if server.Name == reference.Name {
+				ids = append(ids, server.ID)
+				found = true
+			}
+		}
+		if !found {
+			if required {
+				return nil, fmt.Errorf("failed to find server [Name=%q]: %w", reference.Name, ErrNotFound)
+			}
+			klog.V(3).Infof("no server [Name=%q] found, skipping scheduler hint", reference.Name)
+		}
+	}
+	return ids, nil
+}
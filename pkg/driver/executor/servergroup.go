@@ -0,0 +1,154 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package executor
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/servergroups"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+
+	"github.com/gardener/machine-controller-manager-provider-openstack/pkg/apis/cloudprovider"
+)
+
+// ensureServerGroup returns the ID of the managed server group and creates the server group if it does not exist yet.
+// Machines of the same machine class may be created concurrently, hence the server groups are listed again after the
+// creation. If a concurrent creation is detected, all machines settle on the server group with the lowest ID and the
+// superfluous server group is deleted again.
+func (ex *Executor) ensureServerGroup(ctx context.Context) (string, error) {
+	name, err := ex.serverGroupName()
+	if err != nil {
+		return "", err
+	}
+	policy := ex.Config.Spec.ManagedServerGroup.Policy
+
+	groups, err := ex.listServerGroups(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	if len(groups) == 0 {
+		klog.V(3).Infof("creating server group [Name=%q, Policy=%q]", name, policy)
+		created, err := ex.Compute.CreateServerGroup(ctx, servergroups.CreateOpts{
+			Name:     name,
+			Policies: []string{policy},
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to create server group [Name=%q]: %w", name, err)
+		}
+
+		groups, err = ex.listServerGroups(ctx, name)
+		if err != nil {
+			return "", err
+		}
+		if len(groups) == 0 {
+			groups = []servergroups.ServerGroup{*created}
+		}
+		if selected := lowestServerGroupID(groups); selected != "" && selected != created.ID {
+			klog.V(3).Infof("server group [Name=%q] was created concurrently, deleting server group [ID=%q]", name, created.ID)
+			if err := ex.Compute.DeleteServerGroup(ctx, created.ID); err != nil {
+				return "", fmt.Errorf("failed to delete superfluous server group [ID=%q]: %w", created.ID, err)
+			}
+		}
+	}
+
+	id := lowestServerGroupID(groups)
+	if id == "" {
+		return "", fmt.Errorf("server group [Name=%q]: %w", name, ErrNotFound)
+	}
+	for _, group := range groups {
+		if group.ID == id && !hasServerGroupPolicy(group, policy) {
+			return "", fmt.Errorf("server group [Name=%q, ID=%q] exists with policies %v instead of %q", name, id, group.Policies, policy)
+		}
+	}
+	return id, nil
+}
+
+// deleteServerGroupIfEmpty deletes the managed server group once its last member has been deleted. A machine that is
+// created concurrently may be scheduled while the server group is deleted, which checkServerGroupMembership detects, so
+// that its creation is retried with a newly created server group.
+func (ex *Executor) deleteServerGroupIfEmpty(ctx context.Context) error {
+	name, err := ex.serverGroupName()
+	if err != nil {
+		return err
+	}
+
+	groups, err := ex.listServerGroups(ctx, name)
+	if err != nil {
+		return err
+	}
+	for _, group := range groups {
+		if len(group.Members) > 0 {
+			klog.V(3).Infof("server group [Name=%q, ID=%q] still has %d members", name, group.ID, len(group.Members))
+			continue
+		}
+		klog.V(3).Infof("deleting empty server group [Name=%q, ID=%q]", name, group.ID)
+		if err := ex.Compute.DeleteServerGroup(ctx, group.ID); err != nil {
+			return fmt.Errorf("failed to delete server group [ID=%q]: %w", group.ID, err)
+		}
+	}
+	return nil
+}
+
+// checkServerGroupMembership verifies that the server is a member of the managed server group. The server group is
+// deleted by deleteServerGroupIfEmpty once its last member is gone, hence a server created concurrently may have been
+// scheduled without the server group. Such a server fails the creation, which is retried with a recreated server group.
+func (ex *Executor) checkServerGroupMembership(ctx context.Context, serverID string) error {
+	name, err := ex.serverGroupName()
+	if err != nil {
+		return err
+	}
+
+	groups, err := ex.listServerGroups(ctx, name)
+	if err != nil {
+		return err
+	}
+	for _, group := range groups {
+		if slices.Contains(group.Members, serverID) {
+			return nil
+		}
+	}
+	return fmt.Errorf("server [ID=%q] is not a member of server group [Name=%q], which was deleted during the creation", serverID, name)
+}
+
+// serverGroupName returns the name of the managed server group. Nova server groups can neither be tagged nor filtered,
+// therefore the cluster and the role of the machine class are encoded in the name.
+func (ex *Executor) serverGroupName() (string, error) {
+	searchClusterName, searchNodeRole, ok := findMandatoryTags(ex.Config.Spec.Tags)
+	if !ok {
+		klog.Warningf("operation can not proceed: cluster/role tags are missing")
+		return "", fmt.Errorf("operation can not proceed: cluster/role tags are missing")
+	}
+	_, clusterName, _ := strings.Cut(searchClusterName, cloudprovider.ServerTagClusterPrefix)
+	_, nodeRole, _ := strings.Cut(searchNodeRole, cloudprovider.ServerTagRolePrefix)
+	return fmt.Sprintf("%s-%s-%s", clusterName, nodeRole, ex.Config.Spec.ManagedServerGroup.Name), nil
+}
+
+func (ex *Executor) listServerGroups(ctx context.Context, name string) ([]servergroups.ServerGroup, error) {
+	groups, err := ex.Compute.ListServerGroups(ctx, servergroups.ListOpts{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list server groups: %w", err)
+	}
+	return slices.DeleteFunc(groups, func(group servergroups.ServerGroup) bool {
+		return group.Name != name
+	}), nil
+}
+
+func lowestServerGroupID(groups []servergroups.ServerGroup) string {
+	id := ""
+	for _, group := range groups {
+		if id == "" || group.ID < id {
+			id = group.ID
+		}
+	}
+	return id
+}
+
+func hasServerGroupPolicy(group servergroups.ServerGroup, policy string) bool {
+	return slices.Contains(group.Policies, policy) || ptr.Deref(group.Policy, "") == policy
+}
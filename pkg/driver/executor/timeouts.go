@@ -0,0 +1,104 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package executor
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	api "github.com/gardener/machine-controller-manager-provider-openstack/pkg/apis/openstack"
+)
+
+const (
+	defaultServerCreateTimeout = 20 * time.Minute
+	defaultVolumeCreateTimeout = 20 * time.Minute
+	defaultDeleteTimeout       = 20 * time.Minute
+	defaultPollInterval        = 10 * time.Second
+
+	// maxPollInterval caps the exponential growth of the poll interval, unless the configured initial poll interval is
+	// already larger.
+	maxPollInterval = time.Minute
+	// pollBackoffFactor is the factor the poll interval is multiplied with after each poll.
+	pollBackoffFactor = 1.5
+	// pollBackoffJitter is the maximum fraction by which each poll interval is randomly extended, so that many machines
+	// created at the same time do not poll OpenStack in lockstep.
+	pollBackoffJitter = 0.2
+)
+
+// Timeouts contains the timeouts and the initial poll interval used while waiting for OpenStack resources to reach a
+// target status. Zero values are replaced by the built-in defaults.
+type Timeouts struct {
+	// ServerCreate is the maximum duration to wait for a server to become active.
+	ServerCreate time.Duration
+	// VolumeCreate is the maximum duration to wait for a volume to become available.
+	VolumeCreate time.Duration
+	// Delete is the maximum duration to wait for a server or a volume to be deleted.
+	Delete time.Duration
+	// PollInterval is the initial interval between two polls. It grows exponentially with every poll.
+	PollInterval time.Duration
+}
+
+// AddFlags adds the flags for the driver-level default timeouts to the given flag set.
+func (t *Timeouts) AddFlags(fs *pflag.FlagSet) {
+	fs.DurationVar(&t.ServerCreate, "openstack-server-create-timeout", defaultServerCreateTimeout, "Default timeout for a server to become active.")
+	fs.DurationVar(&t.VolumeCreate, "openstack-volume-create-timeout", defaultVolumeCreateTimeout, "Default timeout for a volume to become available.")
+	fs.DurationVar(&t.Delete, "openstack-delete-timeout", defaultDeleteTimeout, "Default timeout for a server or volume to be deleted.")
+	fs.DurationVar(&t.PollInterval, "openstack-poll-interval", defaultPollInterval, "Default initial interval between two status polls, which is increased with exponential backoff.")
+}
+
+// withOverrides returns a copy of the timeouts where zero values are replaced by the built-in defaults and the values
+// set in the provider spec take precedence.
+func (t Timeouts) withOverrides(overrides *api.Timeouts) Timeouts {
+	out := Timeouts{
+		ServerCreate: durationOrDefault(t.ServerCreate, defaultServerCreateTimeout),
+		VolumeCreate: durationOrDefault(t.VolumeCreate, defaultVolumeCreateTimeout),
+		Delete:       durationOrDefault(t.Delete, defaultDeleteTimeout),
+		PollInterval: durationOrDefault(t.PollInterval, defaultPollInterval),
+	}
+	if overrides == nil {
+		return out
+	}
+
+	if overrides.ServerCreate != nil {
+		out.ServerCreate = overrides.ServerCreate.Duration
+	}
+	if overrides.VolumeCreate != nil {
+		out.VolumeCreate = overrides.VolumeCreate.Duration
+	}
+	if overrides.Delete != nil {
+		out.Delete = overrides.Delete.Duration
+	}
+	if overrides.PollInterval != nil {
+		out.PollInterval = overrides.PollInterval.Duration
+	}
+	return out
+}
+
+// pollWithBackoff invokes condition until it returns true, an error, or the timeout expires. The interval between two
+// invocations starts at pollInterval and grows exponentially with jitter up to maxPollInterval.
+func pollWithBackoff(ctx context.Context, pollInterval, timeout time.Duration, condition wait.ConditionWithContextFunc) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	backoff := wait.Backoff{
+		Duration: pollInterval,
+		Factor:   pollBackoffFactor,
+		Jitter:   pollBackoffJitter,
+		Steps:    math.MaxInt32,
+		Cap:      max(pollInterval, maxPollInterval),
+	}
+	return backoff.DelayFunc().Until(ctx, true, true, condition)
+}
+
+func durationOrDefault(d, def time.Duration) time.Duration {
+	if d <= 0 {
+		return def
+	}
+	return d
+}
@@ -8,9 +8,14 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+
 	"github.com/gardener/machine-controller-manager-provider-openstack/pkg/apis/cloudprovider"
 )
 
+// maxServerTagLength is the maximum length of a Nova server tag.
+const maxServerTagLength = 60
+
 // encodeProviderID encodes the ID of a server.
 func encodeProviderID(region string, machineID string) string {
 	return fmt.Sprintf("openstack:///%s/%s", region, machineID)
@@ -62,3 +67,75 @@ func findMandatoryTags(tags map[string]string) (string, string, bool) {
 	}
 	return searchClusterName, searchNodeRole, true
 }
+
+// missingServerMetadata returns the entries of tags which are missing or differ in the server's metadata.
+func missingServerMetadata(server *servers.Server, tags map[string]string) map[string]string {
+	missing := map[string]string{}
+	for k, v := range tags {
+		if current, ok := server.Metadata[k]; !ok || current != v {
+			missing[k] = v
+		}
+	}
+	return missing
+}
+
+// isValidServerTag reports whether the tag can be stored as a Nova server tag, which is limited to maxServerTagLength
+// characters and must neither contain commas nor slashes.
+func isValidServerTag(tag string) bool {
+	return tag != "" && len(tag) <= maxServerTagLength && !strings.ContainsAny(tag, ",/")
+}
+
+// hasOwnershipMarkers reports whether the server carries the cluster and role markers, either as metadata keys or as
+// server tags. Servers created before server tags were supported carry the markers only in their metadata.
+func hasOwnershipMarkers(server *servers.Server, clusterName, nodeRole string) bool {
+	if _, nameOk := server.Metadata[clusterName]; nameOk {
+		if _, roleOk := server.Metadata[nodeRole]; roleOk {
+			return true
+		}
+	}
+	return server.Tags != nil && strSliceContains(*server.Tags, clusterName) && strSliceContains(*server.Tags, nodeRole)
+}
+
+// missingServerTags returns the tags which the server does not carry yet.
+func missingServerTags(server *servers.Server, tags []string) []string {
+	var missing []string
+	for _, tag := range tags {
+		if server.Tags == nil || !strSliceContains(*server.Tags, tag) {
+			missing = append(missing, tag)
+		}
+	}
+	return missing
+}
+
+// dataVolumeName returns the name of the data volume with the given suffix of a machine.
+func dataVolumeName(machineName, suffix string) string {
+	return fmt.Sprintf("%s-%s", machineName, suffix)
+}
+
+// serverMetadata returns the metadata of a server, which consists of the tags and the availability zone and flavor the
+// server is created with.
+func serverMetadata(tags map[string]string, p placement) map[string]string {
+	metadata := make(map[string]string, len(tags)+2)
+	for k, v := range tags {
+		metadata[k] = v
+	}
+	if p.availabilityZone != "" {
+		metadata[cloudprovider.ServerMetadataAvailabilityZone] = p.availabilityZone
+	}
+	if p.flavorName != "" {
+		metadata[cloudprovider.ServerMetadataFlavor] = p.flavorName
+	}
+	return metadata
+}
+
+// isCapacityError returns true if the error indicates that the server could not be scheduled because of insufficient
+// capacity, either when it was requested or when it landed in ERROR status with a scheduling fault.
+func isCapacityError(err error) bool {
+	return err != nil && strings.Contains(err.Error(), NoValidHost)
+}
+
+// isQuotaError returns true if the error indicates that the server could not be created because a quota of the project
+// is exhausted.
+func isQuotaError(err error) bool {
+	return err != nil && strings.Contains(err.Error(), QuotaExceeded)
+}
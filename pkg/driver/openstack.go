@@ -8,6 +8,9 @@ package driver
 import (
 	"github.com/gardener/machine-controller-manager/pkg/util/provider/driver"
 	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/gardener/machine-controller-manager-provider-openstack/pkg/client"
+	"github.com/gardener/machine-controller-manager-provider-openstack/pkg/driver/executor"
 )
 
 var (
@@ -16,12 +19,18 @@ var (
 
 // OpenstackDriver implements and handles requests via the Driver interface.
 type OpenstackDriver struct {
-	decoder runtime.Decoder
+	decoder     runtime.Decoder
+	clientCache *client.FactoryCache
+	timeouts    executor.Timeouts
 }
 
-// NewOpenstackDriver returns a new instance of the Openstack driver.
-func NewOpenstackDriver(decoder runtime.Decoder) driver.Driver {
+// NewOpenstackDriver returns a new instance of the Openstack driver. The timeouts are used as defaults for all machines,
+// unless they are overridden in the provider spec. The rate limits and HTTP options apply to all requests sent to the
+// OpenStack API.
+func NewOpenstackDriver(decoder runtime.Decoder, timeouts executor.Timeouts, rateLimits client.RateLimits, httpOpts client.HTTPOptions) driver.Driver {
 	return &OpenstackDriver{
-		decoder: decoder,
+		decoder:     decoder,
+		clientCache: client.NewFactoryCache(rateLimits, httpOpts),
+		timeouts:    timeouts,
 	}
 }
@@ -5,11 +5,13 @@
 package driver
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"strings"
 
 	"github.com/gardener/machine-controller-manager/pkg/util/provider/machinecodes/codes"
+	"github.com/prometheus/client_golang/prometheus"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 
@@ -46,6 +48,26 @@ func (p *OpenstackDriver) decodeProviderSpec(raw runtime.RawExtension) (*opensta
 	return DecodeProviderSpec(p.decoder, raw)
 }
 
+// driverErrorCount counts the error codes returned by the driver, partitioned by provider, operation, region and code.
+var driverErrorCount = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "mcm",
+	Subsystem: "cloud_api",
+	Name:      "driver_errors_total",
+	Help:      "Number of errors returned by the driver, partitioned by provider, operation, region, and code.",
+}, []string{"provider", "operation", "region", "code"},
+)
+
+func init() {
+	prometheus.MustRegister(driverErrorCount)
+}
+
+// recordErrorCode maps the error of the driver operation to a machine error code and records the code.
+func recordErrorCode(operation, region string, err error) codes.Code {
+	code := mapErrorToCode(err)
+	driverErrorCount.With(prometheus.Labels{"provider": "openstack", "operation": operation, "region": region, "code": code.String()}).Inc()
+	return code
+}
+
 func mapErrorToCode(err error) codes.Code {
 	if errors.Is(err, executor.ErrNotFound) {
 		return codes.NotFound
@@ -55,6 +77,19 @@ func mapErrorToCode(err error) codes.Code {
 		return codes.OutOfRange
 	}
 
+	// the server exists, but does not run
+	if errors.Is(err, executor.ErrServerFailed) || errors.Is(err, executor.ErrServerShutoff) {
+		return codes.Unavailable
+	}
+
+	if errors.Is(err, context.Canceled) {
+		return codes.Canceled
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return codes.DeadlineExceeded
+	}
+
 	if client.IsUnauthenticated(err) {
 		return codes.Unauthenticated
 	}
@@ -66,9 +101,18 @@ func mapErrorToCode(err error) codes.Code {
 	return mapErrorMessageToCode(err)
 }
 
+// mapInitializeErrorToCode maps the error of the initialization to a machine error code. Failed initialization steps
+// are reported as Uninitialized, unless the server is gone or does not run.
+func mapInitializeErrorToCode(err error) codes.Code {
+	if code := mapErrorToCode(err); code == codes.NotFound || code == codes.Unavailable {
+		return code
+	}
+	return codes.Uninitialized
+}
+
 func mapErrorMessageToCode(err error) codes.Code {
 	errorMessage := err.Error()
-	if strings.Contains(errorMessage, executor.NoValidHost) {
+	if strings.Contains(errorMessage, executor.NoValidHost) || strings.Contains(errorMessage, executor.QuotaExceeded) {
 		return codes.ResourceExhausted
 	}
 	return codes.Internal
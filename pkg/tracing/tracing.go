@@ -0,0 +1,97 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package tracing configures the optional OpenTelemetry tracing of the driver.
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/pflag"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	serviceName = "machine-controller-manager-provider-openstack"
+	tracerName  = "github.com/gardener/machine-controller-manager-provider-openstack"
+)
+
+// Options configures the export of traces via OTLP. Tracing is disabled if no endpoint is set.
+type Options struct {
+	// Endpoint is the host and port of the OTLP gRPC receiver.
+	Endpoint string
+	// Insecure disables TLS for the connection to the receiver.
+	Insecure bool
+	// SamplingRatio is the fraction of traces which are sampled, unless the parent span has been sampled already.
+	SamplingRatio float64
+}
+
+// AddFlags adds the flags for the trace export to the given flag set.
+func (o *Options) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.Endpoint, "tracing-otlp-endpoint", "", "Host and port of the OTLP gRPC receiver traces are exported to. Tracing is disabled if empty.")
+	fs.BoolVar(&o.Insecure, "tracing-otlp-insecure", false, "Disable TLS for the connection to the OTLP receiver.")
+	fs.Float64Var(&o.SamplingRatio, "tracing-sampling-ratio", 1, "Fraction of the driver requests which are traced.")
+}
+
+// Setup installs the global tracer provider exporting to the configured endpoint. The returned function flushes and
+// stops the export. If tracing is disabled, the global no-op tracer provider is kept.
+func Setup(ctx context.Context, opts Options) (func(context.Context) error, error) {
+	if opts.Endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporterOpts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(opts.Endpoint)}
+	if opts.Insecure {
+		exporterOpts = append(exporterOpts, otlptracegrpc.WithInsecure())
+	}
+	exporter, err := otlptracegrpc.New(ctx, exporterOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(serviceName)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opts.SamplingRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Start starts a span, which is a child of the span in the context if there is one.
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, opts...)
+}
+
+// End records the error, if any, and ends the span.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// MachineName returns the attribute identifying the machine a span belongs to.
+func MachineName(name string) attribute.KeyValue {
+	return attribute.String("machine.name", name)
+}
+
+// MachineClassName returns the attribute identifying the machine class a span belongs to.
+func MachineClassName(name string) attribute.KeyValue {
+	return attribute.String("machine_class.name", name)
+}
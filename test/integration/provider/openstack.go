@@ -24,7 +24,7 @@ func getOrphanedInstances(factory *client.Factory) ([]string, error) {
 		return nil, err
 	}
 
-	instances, err := compute.ListServers(&servers.ListOpts{})
+	instances, err := compute.ListServers(context.Background(), &servers.ListOpts{})
 	if err != nil {
 		return nil, err
 	}
@@ -43,7 +43,7 @@ func getMachines(machineClass *v1alpha1.MachineClass, factory *client.Factory) (
 	if err != nil {
 		return nil, err
 	}
-	ex, err := executor.NewExecutor(factory, providerConfig)
+	ex, err := executor.NewExecutor(factory, providerConfig, executor.Timeouts{})
 	if err != nil {
 		return nil, err
 	}
@@ -68,7 +68,7 @@ func getOrphanedNICs(factory *client.Factory) ([]string, error) {
 		return nil, err
 	}
 
-	ports, err := network.ListPorts(&ports.ListOpts{
+	ports, err := network.ListPorts(context.Background(), &ports.ListOpts{
 		Tags: ITResourceTagKey,
 	})
 	if err != nil {
@@ -87,7 +87,7 @@ func getOrphanedDisks(factory *client.Factory) ([]string, error) {
 		return nil, err
 	}
 
-	vols, err := storage.ListVolumes(volumes.ListOpts{})
+	vols, err := storage.ListVolumes(context.Background(), volumes.ListOpts{})
 	if err != nil {
 		return nil, err
 	}
@@ -122,7 +122,7 @@ func cleanOrphanResources(orphanVms []string, orphanVolumes []string, orphanNICs
 		compute, err := factory.Compute()
 		if err == nil {
 			for _, instanceID := range orphanVms {
-				if err := compute.DeleteServer(instanceID); err != nil {
+				if err := compute.DeleteServer(context.Background(), instanceID); err != nil {
 					fmt.Printf("failed to delete instance %v: %v", instanceID, err)
 					delErrOrphanVms = append(delErrOrphanVms, instanceID)
 				}
@@ -136,7 +136,7 @@ func cleanOrphanResources(orphanVms []string, orphanVolumes []string, orphanNICs
 		network, err := factory.Network()
 		if err == nil {
 			for _, portID := range orphanNICs {
-				if err := network.DeletePort(portID); err != nil {
+				if err := network.DeletePort(context.Background(), portID); err != nil {
 					fmt.Printf("failed to delete port %v: %v", portID, err)
 					delErrOrphanNICs = append(delErrOrphanNICs, portID)
 				}
@@ -150,7 +150,7 @@ func cleanOrphanResources(orphanVms []string, orphanVolumes []string, orphanNICs
 		storage, err := factory.Storage()
 		if err == nil {
 			for _, volumeID := range orphanVolumes {
-				if err := storage.DeleteVolume(volumeID); err != nil {
+				if err := storage.DeleteVolume(context.Background(), volumeID); err != nil {
 					fmt.Printf("failed to delete volume %v: %v", volumeID, err)
 					delErrOrphanNICs = append(delErrOrphanVolumes, volumeID)
 				}